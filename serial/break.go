@@ -0,0 +1,31 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"fmt"
+	"time"
+)
+
+// The shortest break that can be timed by sleeping between SetBreak calls.
+const MinBreakDuration = time.Millisecond
+
+// SendBreak holds the line in the break condition for the given duration.
+//
+// Pending output is not waited for, so the break may cut off the last bytes.
+// Call Drain first if this matters.
+// Durations shorter than the OS can produce return ErrBreakTooShort.
+// On Linux a driver without break support is not detected, see SetBreak.
+func (p *serialPort) SendBreak(d time.Duration) error {
+	if d < MinBreakDuration {
+		return fmt.Errorf("%w: %v, minimum is %v", ErrBreakTooShort, d, MinBreakDuration)
+	}
+	if err := p.SetBreak(true); err != nil {
+		return err
+	}
+	time.Sleep(d)
+	return p.SetBreak(false)
+}
@@ -0,0 +1,28 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxSendBreak(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	if err := port.SendBreak(serial.MinBreakDuration / 2); !errors.Is(err, serial.ErrBreakTooShort) {
+		t.Errorf("expect ErrBreakTooShort, got %v", err)
+	}
+
+	start := time.Now()
+	if err := port.SendBreak(time.Millisecond * 30); err != nil {
+		t.Fatalf("send break error: %s", err)
+	}
+	checkDuration(t, time.Since(start), time.Millisecond*30, linuxTimeoutAccuracy)
+}
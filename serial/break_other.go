@@ -0,0 +1,12 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+func (p *serialPort) SetBreak(_ bool) error {
+	return ErrNotImplementedOnOS
+}
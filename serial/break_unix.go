@@ -0,0 +1,24 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "golang.org/x/sys/unix"
+
+// SetBreak starts (true) or stops (false) the break condition with TIOCSBRK/TIOCCBRK.
+//
+// The Linux tty layer accepts both requests even if the driver can't send a break,
+// so such drivers can't be detected: the call succeeds and the line is not changed.
+func (p *serialPort) SetBreak(on bool) error {
+	req := uintptr(unix.TIOCCBRK)
+	if on {
+		req = unix.TIOCSBRK
+	}
+	return p.control(func(fd uintptr) error {
+		return ioctl(fd, req, 0)
+	})
+}
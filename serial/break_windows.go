@@ -0,0 +1,19 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+// SetBreak starts (true) or stops (false) the break condition with SetCommBreak/ClearCommBreak.
+func (p *serialPort) SetBreak(on bool) error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+	if on {
+		return setCommBreak(p.fd)
+	}
+	return clearCommBreak(p.fd)
+}
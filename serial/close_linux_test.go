@@ -0,0 +1,178 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// These tests are meant to be run with the race detector (go test -race),
+// which checks that Close does not race with pending IO calls.
+
+func TestLinuxCloseUnblocksRead(t *testing.T) {
+	_, name := openPty(t)
+
+	// Read waits for 4 bytes forever.
+	opt := newPtyOpenOptions(name)
+	opt.MinimumReadSize = 4
+	opt.InterCharacterTimeout = 0
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := port.Read(make([]byte, 16))
+		result <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := port.Close(); err != nil {
+		t.Fatalf("close error: %s", err)
+	}
+	checkClosed(t, result)
+}
+
+func TestLinuxCloseUnblocksWrite(t *testing.T) {
+	_, name := openPty(t)
+	port, err := serial.Open(newPtyOpenOptions(name))
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	// Nobody reads the master side, so Write blocks once the pty buffer is full.
+	result := make(chan error, 1)
+	go func() {
+		_, err := port.Write(make([]byte, 1<<20))
+		result <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := port.Close(); err != nil {
+		t.Fatalf("close error: %s", err)
+	}
+	checkClosed(t, result)
+}
+
+// The output of a pty is never stopped, so the drain is made to wait for Close.
+func TestLinuxCloseUnblocksDrain(t *testing.T) {
+	_, name := openPty(t)
+	port, err := serial.Open(newPtyOpenOptions(name))
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	// Like flow control holding the output, but the queue is still checked,
+	// which fails once the port is closed.
+	stopped := func() (bool, error) {
+		_, err := port.OutputWaiting()
+		return false, err
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- port.DrainWith(context.Background(), stopped)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- port.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("close error: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close is blocked by the pending drain")
+	}
+	checkClosed(t, result)
+}
+
+// tcdrain can't be interrupted, so Close must not wait for it.
+func TestLinuxCloseDoesNotWaitForTcdrain(t *testing.T) {
+	_, name := openPty(t)
+	port, err := serial.Open(newPtyOpenOptions(name))
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	// Like tcdrain with the output stopped by flow control.
+	release := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- port.WaitControl(func(uintptr) error {
+			<-release
+			return nil
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- port.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("close error: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close is blocked by the pending tcdrain")
+	}
+	close(release)
+	if err := <-result; err != nil {
+		t.Errorf("expect the wait to finish, got %v", err)
+	}
+}
+
+func TestLinuxCloseConcurrent(t *testing.T) {
+	_, name := openPty(t)
+	port, err := serial.Open(newPtyOpenOptions(name))
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := port.Close(); err != nil {
+				t.Errorf("close error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := port.Close(); err != nil {
+		t.Errorf("expect repeated close to succeed, got %v", err)
+	}
+	if _, err := port.Read(make([]byte, 1)); !errors.Is(err, serial.ErrPortClosed) {
+		t.Errorf("expect ErrPortClosed from Read, got %v", err)
+	}
+	if _, err := port.Write([]byte{1}); !errors.Is(err, serial.ErrPortClosed) {
+		t.Errorf("expect ErrPortClosed from Write, got %v", err)
+	}
+	if _, err := port.InputWaiting(); !errors.Is(err, serial.ErrPortClosed) {
+		t.Errorf("expect ErrPortClosed from InputWaiting, got %v", err)
+	}
+}
+
+func checkClosed(t *testing.T, result <-chan error) {
+	t.Helper()
+	select {
+	case err := <-result:
+		if !errors.Is(err, serial.ErrPortClosed) {
+			t.Errorf("expect ErrPortClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending IO has not been woken up by Close")
+	}
+}
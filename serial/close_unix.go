@@ -0,0 +1,64 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// The longest time Close waits for the output before the settings are restored.
+const closeDrainTimeout = time.Second
+
+// Close restores the settings that the port had before Open (see `OpenOptions.KeepSettingsOnClose`)
+// and the low latency settings, gives up the exclusive use of the port,
+// closes it and removes the lock file.
+//
+// The settings are restored once the pending output has been transmitted, or after
+// `closeDrainTimeout`, so the last bytes are not sent with the old baud rate or RS485 mode.
+// A device that is gone has nothing to restore or unlock, so that does not make Close fail.
+//
+// Close may be called from any goroutine and more than once, only the first call does the work.
+// A pending Read or Write is woken up and returns ErrPortClosed, and so do all later calls.
+func (p *serialPort) Close() error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	var err error
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		if p.snapshot != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), closeDrainTimeout)
+			p.DrainContext(ctx)
+			cancel()
+		}
+		err = ignoreRemoved(p.restoreSnapshot())
+		err = errors.Join(err, ignoreRemoved(p.restoreLowLatency()))
+		err = errors.Join(err, ignoreRemoved(p.unlockExclusive()))
+		err = errors.Join(err, p.File.Close())
+		err = errors.Join(err, p.lock.release())
+	})
+	return err
+}
+
+func ignoreRemoved(err error) error {
+	if errors.Is(err, ErrDeviceRemoved) {
+		return nil
+	}
+	return err
+}
+
+// Replaces the error of an operation that has been interrupted by Close with ErrPortClosed,
+// and makes other errors match the sentinel errors (see `ioError`).
+func (p *serialPort) wrapError(err error) error {
+	if err != nil && p != nil && p.closed.Load() {
+		return ErrPortClosed
+	}
+	return ioError(err)
+}
@@ -0,0 +1,41 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Close restores the settings that the port had before Open
+// (see `OpenOptions.KeepSettingsOnClose`) and closes it.
+//
+// Close may be called from any goroutine and more than once, only the first call does the work.
+// A pending Read or Write is cancelled with CancelIoEx and returns ErrPortClosed, and so do all later calls.
+func (p *serialPort) Close() error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+	var err error
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		cancelIoEx(p.fd)
+		err = p.restoreSnapshot()
+		// An IO call may have been started in the meantime, cancel it too.
+		cancelIoEx(p.fd)
+		err = errors.Join(err, syscall.CloseHandle(p.fd))
+	})
+	return err
+}
+
+// Replaces the error of an operation that has been interrupted by Close with ErrPortClosed,
+// and makes other errors match the sentinel errors (see `ioError`).
+func (p *serialPort) wrapError(err error) error {
+	if err != nil && p != nil && p.closed.Load() {
+		return ErrPortClosed
+	}
+	return ioError(err)
+}
@@ -0,0 +1,68 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxReadContext(t *testing.T) {
+	master, name := openPty(t)
+
+	// Blocking read of up to 10 bytes, no timers at all.
+	opt := newPtyOpenOptions(name)
+	opt.MinimumReadSize = 10
+	opt.InterCharacterTimeout = 0
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	master.Write([]byte{1, 2, 3})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	start := time.Now()
+	n, err := port.ReadContext(ctx, make([]byte, 10))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expect context.DeadlineExceeded, got %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expect partial read of 3 bytes, got %d", n)
+	}
+	checkDuration(t, time.Since(start), time.Millisecond*50, linuxTimeoutAccuracy)
+
+	// The port is still usable.
+	master.Write([]byte("0123456789"))
+	buf := make([]byte, 10)
+	if n, err := port.Read(buf); err != nil || !bytes.Equal(buf[:n], []byte("0123456789")) {
+		t.Errorf("expect the data to be read after cancellation, got %q and %v", buf[:n], err)
+	}
+}
+
+func TestLinuxWriteContext(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*50, cancel)
+
+	// Nobody reads the remote side, so the write stalls once the pty buffers are full.
+	data := make([]byte, 1<<20)
+	n, err := port.WriteContext(ctx, data)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context.Canceled, got %v", err)
+	}
+	if n == 0 || n >= len(data) {
+		t.Errorf("expect partial write, got %d of %d bytes", n, len(data))
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "context"
+
+// ReadContext reads data like Read does.
+// On target OS a pending syscall can't be interrupted,
+// so ctx is only checked before and after the call.
+func (p *serialPort) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.Read(buf)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return n, ctxErr
+	}
+	return n, err
+}
+
+// WriteContext writes data like Write does.
+// On target OS a pending syscall can't be interrupted,
+// so ctx is only checked before and after the call.
+func (p *serialPort) WriteContext(ctx context.Context, buf []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.Write(buf)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return n, ctxErr
+	}
+	return n, err
+}
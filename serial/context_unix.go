@@ -0,0 +1,59 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"time"
+)
+
+// A deadline in the past makes the runtime poller wake up a pending Read/Write immediately.
+var aLongTimeAgo = time.Unix(1, 0)
+
+// ReadContext reads data like Read does, but returns as soon as ctx is done.
+// In this case it returns the number of bytes read so far and ctx.Err().
+// The port stays usable after the cancellation.
+func (p *serialPort) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+	defer interruptOnDone(ctx, p.File.SetReadDeadline)()
+	n, err := p.read(ctx, buf)
+	return n, p.wrapError(err)
+}
+
+// WriteContext writes data like Write does, but returns as soon as ctx is done.
+// In this case it returns the number of bytes written so far and ctx.Err().
+// The port stays usable after the cancellation.
+func (p *serialPort) WriteContext(ctx context.Context, buf []byte) (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+	defer interruptOnDone(ctx, p.File.SetWriteDeadline)()
+	n, err := p.write(ctx, buf)
+	return n, p.wrapError(err)
+}
+
+// Moves the deadline to the past when ctx is done, which interrupts a pending syscall.
+// The returned function must be called when the IO operation is over.
+// It waits for a running interruption, so it can't affect the next operation.
+func interruptOnDone(ctx context.Context, setDeadline func(time.Time) error) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		setDeadline(aLongTimeAgo)
+		close(done)
+	})
+	return func() {
+		if !stop() {
+			<-done
+		}
+	}
+}
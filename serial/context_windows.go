@@ -0,0 +1,78 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReadContext reads data like Read does, but returns as soon as ctx is done.
+// In this case it returns the number of bytes read so far and ctx.Err().
+// The port stays usable after the cancellation.
+func (p *serialPort) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	return p.doContext(ctx, func() (int, error) { return p.Read(buf) })
+}
+
+// WriteContext writes data like Write does, but returns as soon as ctx is done.
+// In this case it returns the number of bytes written so far and ctx.Err().
+// The port stays usable after the cancellation.
+func (p *serialPort) WriteContext(ctx context.Context, buf []byte) (int, error) {
+	return p.doContext(ctx, func() (int, error) { return p.Write(buf) })
+}
+
+// Runs a synchronous IO operation and cancels it with CancelSynchronousIo when ctx is done.
+func (p *serialPort) doContext(ctx context.Context, op func() (int, error)) (int, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return 0, ErrInvalidOrNilPort
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if ctx.Done() == nil {
+		return op()
+	}
+
+	// The cancellation is addressed to the thread that performs the IO.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	thread, err := openCurrentThread()
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.CloseHandle(thread)
+
+	var mu sync.Mutex
+	finished := false
+	stop := context.AfterFunc(ctx, func() {
+		// The IO may not be started yet, so repeat until the operation is over.
+		for {
+			mu.Lock()
+			if finished {
+				mu.Unlock()
+				return
+			}
+			cancelSynchronousIo(thread)
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	n, err := op()
+
+	mu.Lock()
+	finished = true
+	mu.Unlock()
+	stop()
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return n, ctxErr
+	}
+	return n, err
+}
@@ -0,0 +1,47 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Counters are the statistics kept by the driver since it was loaded.
+// The counters are 32 bit and wrap around, use Delta to compare two snapshots.
+type Counters struct {
+	// Bytes received and transmitted.
+	RX, TX uint32
+
+	// Receive errors.
+	Frame         uint32 // framing errors
+	Overrun       uint32 // UART overruns
+	Parity        uint32 // parity errors
+	Break         uint32 // break conditions
+	BufferOverrun uint32 // bytes dropped because the tty buffer was full
+
+	// Transitions of modem input lines.
+	CTS, DSR, RI, DCD uint32
+}
+
+// Delta returns the changes of the counters since the prev snapshot.
+// A counter that has wrapped around once in between is still correct.
+func (c Counters) Delta(prev Counters) Counters {
+	return Counters{
+		RX:            c.RX - prev.RX,
+		TX:            c.TX - prev.TX,
+		Frame:         c.Frame - prev.Frame,
+		Overrun:       c.Overrun - prev.Overrun,
+		Parity:        c.Parity - prev.Parity,
+		Break:         c.Break - prev.Break,
+		BufferOverrun: c.BufferOverrun - prev.BufferOverrun,
+		CTS:           c.CTS - prev.CTS,
+		DSR:           c.DSR - prev.DSR,
+		RI:            c.RI - prev.RI,
+		DCD:           c.DCD - prev.DCD,
+	}
+}
+
+// Errors returns the total of the receive error counters, breaks excluded.
+// Applied to a Delta it gives the number of errors in a period of time.
+func (c Counters) Errors() uint32 {
+	return c.Frame + c.Overrun + c.Parity + c.BufferOverrun
+}
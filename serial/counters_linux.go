@@ -0,0 +1,50 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Type from linux/serial.h
+type serial_icounter_struct struct {
+	cts, dsr, rng, dcd int32
+	rx, tx             int32
+	frame, overrun     int32
+	parity, brk        int32
+	buf_overrun        int32
+	reserved           [9]int32
+}
+
+// Counters returns the driver statistics read with TIOCGICOUNT.
+// Not every driver supports it, e.g. USB CDC ACM devices and ptys don't.
+func (p *serialPort) Counters() (Counters, error) {
+	if p == nil || p.File == nil {
+		return Counters{}, ErrInvalidOrNilPort
+	}
+	var ic serial_icounter_struct
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCGICOUNT, uintptr(unsafe.Pointer(&ic)))
+	})
+	if err != nil {
+		return Counters{}, err
+	}
+	return Counters{
+		RX:            uint32(ic.rx),
+		TX:            uint32(ic.tx),
+		Frame:         uint32(ic.frame),
+		Overrun:       uint32(ic.overrun),
+		Parity:        uint32(ic.parity),
+		Break:         uint32(ic.brk),
+		BufferOverrun: uint32(ic.buf_overrun),
+		CTS:           uint32(ic.cts),
+		DSR:           uint32(ic.dsr),
+		RI:            uint32(ic.rng),
+		DCD:           uint32(ic.dcd),
+	}, nil
+}
@@ -0,0 +1,20 @@
+package serial
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountersDelta(t *testing.T) {
+	prev := Counters{RX: math.MaxUint32 - 1, TX: 10, Frame: 1, Parity: 2, Overrun: 3, BufferOverrun: 4, Break: 5, DCD: 6}
+	cur := Counters{RX: 3, TX: 15, Frame: 2, Parity: 4, Overrun: 3, BufferOverrun: 5, Break: 7, DCD: 6}
+
+	expected := Counters{RX: 5, TX: 5, Frame: 1, Parity: 2, BufferOverrun: 1, Break: 2}
+	delta := cur.Delta(prev)
+	if delta != expected {
+		t.Errorf("expected %+v, but got %+v", expected, delta)
+	}
+	if errors := delta.Errors(); errors != 4 {
+		t.Errorf("expected 4 errors, but got %d", errors)
+	}
+}
@@ -0,0 +1,84 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func openBlockingPtyPort(t *testing.T) (*serial.Port, *os.File) {
+	t.Helper()
+	master, name := openPty(t)
+	opt := newPtyOpenOptions(name)
+	opt.MinimumReadSize = 1
+	opt.InterCharacterTimeout = 0
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	t.Cleanup(func() { port.Close() })
+	return port, master
+}
+
+func TestLinuxReadDeadline(t *testing.T) {
+	port, master := openBlockingPtyPort(t)
+
+	start := time.Now()
+	port.SetReadDeadline(start.Add(time.Millisecond * 50))
+	n, err := port.Read(make([]byte, 1))
+	if n != 0 || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expect 0 and os.ErrDeadlineExceeded, got %d and %v", n, err)
+	}
+	checkDuration(t, time.Since(start), time.Millisecond*50, linuxTimeoutAccuracy)
+
+	// The deadline has passed, so even available data is not read.
+	master.Write([]byte{1})
+	time.Sleep(time.Millisecond * 20)
+	if _, err := port.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expect os.ErrDeadlineExceeded, got %v", err)
+	}
+
+	// Clearing the deadline makes the port usable again.
+	port.SetReadDeadline(time.Time{})
+	if n, err := port.Read(make([]byte, 1)); n != 1 || err != nil {
+		t.Errorf("expect 1 byte, got %d and %v", n, err)
+	}
+}
+
+func TestLinuxReadDeadlineChangedWhileBlocked(t *testing.T) {
+	port, _ := openBlockingPtyPort(t)
+
+	time.AfterFunc(time.Millisecond*20, func() {
+		port.SetReadDeadline(time.Now().Add(time.Millisecond * 30))
+	})
+
+	start := time.Now()
+	_, err := port.Read(make([]byte, 1))
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expect os.ErrDeadlineExceeded, got %v", err)
+	}
+	checkDuration(t, time.Since(start), time.Millisecond*50, linuxTimeoutAccuracy)
+}
+
+func TestLinuxWriteDeadline(t *testing.T) {
+	port, _ := openBlockingPtyPort(t)
+
+	// Nobody reads the remote side, so the write stalls once the pty buffers are full.
+	data := make([]byte, 1<<20)
+	port.SetWriteDeadline(time.Now().Add(time.Millisecond * 50))
+	n, err := port.Write(data)
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("expect os.ErrDeadlineExceeded, got %v", err)
+	}
+	if n == 0 || n >= len(data) {
+		t.Errorf("expect partial write, got %d of %d bytes", n, len(data))
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "time"
+
+// Deadlines require the runtime poller, which is not used on target OS.
+// Use `SetTimeouts` or `ReadContext`/`WriteContext` instead.
+func (p *serialPort) SetDeadline(_ time.Time) error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) SetReadDeadline(_ time.Time) error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) SetWriteDeadline(_ time.Time) error {
+	return ErrNotImplementedOnOS
+}
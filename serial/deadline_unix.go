@@ -0,0 +1,65 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "time"
+
+// SetDeadline sets the read and write deadlines, like net.Conn does.
+// A zero value for t means IO operations will not time out.
+func (p *serialPort) SetDeadline(t time.Time) error {
+	if err := p.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return p.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for future Read calls and any currently-blocked Read call.
+// A Read that passes the deadline returns an error that satisfies `os.ErrDeadlineExceeded` (`ErrTimeout`).
+//
+// The deadline works together with the timeouts: whichever comes first ends the Read,
+// but only the deadline is reported as an error.
+func (p *serialPort) SetReadDeadline(t time.Time) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	p.mu.Lock()
+	p.readDeadline = t
+	p.readGen++
+	p.mu.Unlock()
+
+	// Wake up a pending Read, so it picks up the new deadline.
+	return p.File.SetReadDeadline(aLongTimeAgo)
+}
+
+// SetWriteDeadline sets the deadline for future Write calls and any currently-blocked Write call.
+// A Write that passes the deadline returns an error that satisfies `os.ErrDeadlineExceeded` (`ErrTimeout`).
+// Even if write times out, it may return n > 0, indicating that some of the data was successfully written.
+func (p *serialPort) SetWriteDeadline(t time.Time) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	p.mu.Lock()
+	p.writeDeadline = t
+	p.writeGen++
+	p.mu.Unlock()
+
+	// Wake up a pending Write, so it picks up the new deadline.
+	return p.File.SetWriteDeadline(aLongTimeAgo)
+}
+
+func (p *serialPort) userReadDeadline() (time.Time, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.readDeadline, p.readGen
+}
+
+func (p *serialPort) userWriteDeadline() (time.Time, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.writeDeadline, p.writeGen
+}
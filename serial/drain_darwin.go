@@ -0,0 +1,13 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "golang.org/x/sys/unix"
+
+// tcdrain is TIOCDRAIN on macOS.
+func tcdrain(fd uintptr) error {
+	return ioctl(fd, unix.TIOCDRAIN, 0)
+}
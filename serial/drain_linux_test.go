@@ -0,0 +1,34 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// A pty has no transmitter, so this only checks that the calls are accepted.
+// Real waiting can be observed on hardware only.
+func TestLinuxDrain(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	if _, err := port.Write([]byte("data")); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+	if err := port.Drain(); err != nil {
+		t.Errorf("drain error: %s", err)
+	}
+	if err := port.DrainContext(context.Background()); err != nil {
+		t.Errorf("drain error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := port.DrainContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expect context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "context"
+
+func (p *serialPort) Drain() error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) DrainContext(_ context.Context) error {
+	return ErrNotImplementedOnOS
+}
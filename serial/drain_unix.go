@@ -0,0 +1,76 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"time"
+)
+
+// Interval of output queue polling in DrainContext.
+const drainPollInterval = time.Millisecond
+
+// Drain blocks until all written data has been transmitted, including the last stop bit.
+// It is tcdrain, which also waits for the FIFO of USB adapters.
+//
+// tcdrain can't be interrupted, so it is called by `waitControl`:
+// Close from another goroutine does not wait for a pending Drain.
+func (p *serialPort) Drain() error {
+	return p.wrapError(p.waitControl(tcdrain))
+}
+
+// DrainContext waits like Drain does, but returns ctx.Err() as soon as ctx is done.
+//
+// The output queue is polled with TIOCOUTQ until it is empty,
+// then Drain waits for the last bytes to leave the UART, which is short
+// unless the output is stopped by flow control. If ctx is done during that wait,
+// tcdrain is left to finish in the background.
+func (p *serialPort) DrainContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ctx.Done() == nil {
+		return p.Drain()
+	}
+	if err := p.drainWith(ctx, p.outputQueueEmpty); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Drain()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Polls drained until it reports that the output queue is empty.
+func (p *serialPort) drainWith(ctx context.Context, drained func() (bool, error)) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := drained()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *serialPort) outputQueueEmpty() (bool, error) {
+	n, err := p.OutputWaiting()
+	return n == 0, err
+}
@@ -0,0 +1,28 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"syscall"
+)
+
+// Drain blocks until all written data has been transmitted.
+// It is done with FlushFileBuffers.
+func (p *serialPort) Drain() error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+	return p.wrapError(syscall.FlushFileBuffers(p.fd))
+}
+
+// DrainContext waits like Drain does, but returns ctx.Err() as soon as ctx is done.
+func (p *serialPort) DrainContext(ctx context.Context) error {
+	_, err := p.doContext(ctx, func() (int, error) {
+		return 0, p.Drain()
+	})
+	return err
+}
@@ -5,9 +5,90 @@
 
 package serial
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Sentinel errors to check with errors.Is.
+// The errors of all OSes are wrapped so that they match these errors the same way,
+// while errors.As still finds the original *os.SyscallError or syscall.Errno.
+var (
+	ErrPortNotFound  = errors.New("port not found")
+	ErrPortBusy      = errors.New("port is busy")
+	ErrDeviceRemoved = errors.New("device removed")
+
+	// ErrPortClosed is returned by IO calls after Close. It also matches os.ErrClosed.
+	ErrPortClosed error = &kindError{errors.New("port is closed"), os.ErrClosed}
+
+	// The errors of the standard library are used for the generic conditions,
+	// so e.g. the error of an expired deadline matches ErrTimeout.
+	ErrPermissionDenied = os.ErrPermission
+	ErrTimeout          = os.ErrDeadlineExceeded
+	ErrUnsupported      = errors.ErrUnsupported
+)
 
 var (
-	ErrNotImplementedOnOS = errors.New("not implemented on this OS")
-	ErrInvalidOrNilPort   = errors.New("invalid port")
+	ErrNotImplementedOnOS error = &kindError{errors.New("not implemented on this OS"), ErrUnsupported}
+	ErrInvalidOrNilPort         = errors.New("invalid port")
+	ErrBreakTooShort            = errors.New("break duration is too short")
+	ErrLineErrorsDisabled       = errors.New("line error reporting is not enabled")
 )
+
+// PortBusyError is returned by Open when the port is used by another process.
+// It matches ErrPortBusy with errors.Is.
+type PortBusyError struct {
+	Port string
+	PID  int // Owner of the port, or 0 if unknown.
+}
+
+func (e *PortBusyError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("port %s is busy, locked by PID %d", e.Port, e.PID)
+	}
+	return fmt.Sprintf("port %s is busy", e.Port)
+}
+
+func (e *PortBusyError) Unwrap() error {
+	return ErrPortBusy
+}
+
+// OptionError is returned by Open and Reconfigure for an invalid setting,
+// e.g. `&OptionError{Field: "StopBits", Value: 3}`.
+type OptionError struct {
+	Field  string // Name of the option.
+	Value  any    // The rejected value.
+	Reason string // Optional details.
+}
+
+func (e *OptionError) Error() string {
+	msg := fmt.Sprintf("invalid setting for %s: %v", e.Field, e.Value)
+	if e.Reason != "" {
+		msg += ", " + e.Reason
+	}
+	return msg
+}
+
+// kindError is an error that also matches one of the sentinel errors.
+// The message is the one of the original error.
+type kindError struct {
+	err  error // the original error
+	kind error // the sentinel error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
+
+// Adds the sentinel error to err, if there is one.
+func withKind(err, kind error) error {
+	if err == nil || kind == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &kindError{err, kind}
+}
@@ -0,0 +1,58 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxOpenErrors(t *testing.T) {
+	_, name := openPty(t)
+
+	_, err := serial.Open(newPtyOpenOptions("/dev/ttyNoSuchPort"))
+	if !errors.Is(err, serial.ErrPortNotFound) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect ErrPortNotFound and os.ErrNotExist, got %v", err)
+	}
+
+	opt := newPtyOpenOptions(name)
+	opt.StopBits = 3
+	var optErr *serial.OptionError
+	_, err = serial.Open(opt)
+	if !errors.As(err, &optErr) || optErr.Field != "StopBits" || optErr.Value != uint(3) {
+		t.Errorf("expect OptionError for StopBits, got %v", err)
+	}
+}
+
+func TestLinuxDeviceRemoved(t *testing.T) {
+	master, name := openPty(t)
+	opt := newPtyOpenOptions(name)
+	opt.Exclusive = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	// The slave side of a pty is hung up once the master side is gone,
+	// the same way as the tty of an unplugged USB adapter.
+	master.Close()
+
+	_, err = port.Read(make([]byte, 1))
+	if !errors.Is(err, serial.ErrDeviceRemoved) {
+		t.Errorf("expect ErrDeviceRemoved from Read, got %v", err)
+	}
+	_, err = port.Write([]byte{1})
+	if !errors.Is(err, serial.ErrDeviceRemoved) {
+		t.Errorf("expect ErrDeviceRemoved from Write, got %v", err)
+	}
+	// The settings can't be restored, which is not an error of Close.
+	if err := port.Close(); err != nil {
+		t.Errorf("expect Close to succeed, got %v", err)
+	}
+}
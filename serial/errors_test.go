@@ -0,0 +1,48 @@
+package serial
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"not implemented is unsupported", ErrNotImplementedOnOS, ErrUnsupported},
+		{"port closed is os closed", ErrPortClosed, os.ErrClosed},
+		{"deadline is timeout", os.ErrDeadlineExceeded, ErrTimeout},
+		{"busy", &PortBusyError{Port: "COM1"}, ErrPortBusy},
+		{"selector without matches", &SelectorError{Selector: "by-id:x"}, ErrPortNotFound},
+		{"kind keeps errno", withKind(os.NewSyscallError("read", syscall.EIO), ErrDeviceRemoved), syscall.EIO},
+		{"kind adds sentinel", withKind(os.NewSyscallError("read", syscall.EIO), ErrDeviceRemoved), ErrDeviceRemoved},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if !errors.Is(testCase.err, testCase.target) {
+				t.Errorf("expected %q to match %q", testCase.err, testCase.target)
+			}
+		})
+	}
+
+	ambiguous := &SelectorError{Selector: "usb:0403:6001", Matches: make([]PortInfo, 2)}
+	if errors.Is(ambiguous, ErrPortNotFound) {
+		t.Errorf("expected an ambiguous selector not to match ErrPortNotFound")
+	}
+}
+
+func TestOptionError(t *testing.T) {
+	var err error = &OptionError{Field: "StopBits", Value: 3}
+	if s := err.Error(); s != "invalid setting for StopBits: 3" {
+		t.Errorf("unexpected message %q", s)
+	}
+	err = &OptionError{Field: "InterCharacterTimeout", Value: 0, Reason: "MinimumReadSize is 0 too"}
+	if s := err.Error(); s != "invalid setting for InterCharacterTimeout: 0, MinimumReadSize is 0 too" {
+		t.Errorf("unexpected message %q", s)
+	}
+}
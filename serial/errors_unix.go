@@ -0,0 +1,49 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Wraps an error of open(2) so that it matches the sentinel errors.
+// EBUSY, which open returns for a port in TIOCEXCL mode, gives PortBusyError.
+// EACCES and EPERM match ErrPermissionDenied as they are.
+func openError(name string, err error) error {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return err
+	}
+	switch errno {
+	case syscall.EBUSY:
+		return &PortBusyError{Port: name}
+	case syscall.ENOENT, syscall.ENXIO, syscall.ENODEV:
+		return withKind(err, ErrPortNotFound)
+	}
+	return err
+}
+
+// Wraps an error of IO or ioctl on the open port so that it matches the sentinel errors.
+// A USB adapter that has been unplugged gives EIO, ENXIO or ENODEV,
+// and a driver without the requested ioctl gives ENOTTY.
+func ioError(err error) error {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return err
+	}
+	switch errno {
+	case syscall.EIO, syscall.ENXIO, syscall.ENODEV:
+		return withKind(err, ErrDeviceRemoved)
+	case syscall.ENOTTY:
+		return withKind(err, ErrUnsupported)
+	case syscall.EBUSY:
+		return withKind(err, ErrPortBusy)
+	}
+	return err
+}
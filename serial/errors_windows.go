@@ -0,0 +1,58 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Wraps an error of CreateFile so that it matches the sentinel errors.
+// The port is opened without sharing, so ERROR_ACCESS_DENIED is what another owner causes.
+func openError(name string, err error) error {
+	const ERROR_SHARING_VIOLATION = 32
+
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return err
+	}
+	switch errno {
+	case syscall.ERROR_ACCESS_DENIED, ERROR_SHARING_VIOLATION:
+		return &PortBusyError{Port: name}
+	case syscall.ERROR_FILE_NOT_FOUND, syscall.ERROR_PATH_NOT_FOUND:
+		return withKind(err, ErrPortNotFound)
+	}
+	return err
+}
+
+// Wraps an error of IO or of the communication functions on the open port
+// so that it matches the sentinel errors. Drivers of USB adapters report
+// the removal of the device with one of several codes.
+func ioError(err error) error {
+	const (
+		ERROR_INVALID_FUNCTION     = 1
+		ERROR_BAD_COMMAND          = 22
+		ERROR_GEN_FAILURE          = 31
+		ERROR_NOT_SUPPORTED        = 50
+		ERROR_SEM_TIMEOUT          = 121
+		ERROR_DEVICE_NOT_CONNECTED = 1167
+	)
+
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return err
+	}
+	switch errno {
+	case syscall.ERROR_ACCESS_DENIED, syscall.ERROR_FILE_NOT_FOUND,
+		ERROR_BAD_COMMAND, ERROR_GEN_FAILURE, ERROR_DEVICE_NOT_CONNECTED:
+		return withKind(err, ErrDeviceRemoved)
+	case ERROR_INVALID_FUNCTION, ERROR_NOT_SUPPORTED:
+		return withKind(err, ErrUnsupported)
+	case ERROR_SEM_TIMEOUT:
+		return withKind(err, ErrTimeout)
+	}
+	return err
+}
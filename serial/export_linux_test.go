@@ -0,0 +1,34 @@
+package serial
+
+import (
+	"context"
+	"os"
+)
+
+// DrainWith polls the output like DrainContext does, but asks drained whether the queue is empty.
+func (p *Port) DrainWith(ctx context.Context, drained func() (bool, error)) error {
+	return p.drainWith(ctx, drained)
+}
+
+// WriteMultidropWith sends a multidrop message, but waits for the output with drain.
+func (p *Port) WriteMultidropWith(address byte, data []byte, drain func(context.Context) error) (int, error) {
+	return p.writeMultidrop(address, data, func(mode ParityMode) error {
+		return p.setParityAfter(mode, drain)
+	})
+}
+
+// WaitControl calls f like Drain calls tcdrain.
+func (p *Port) WaitControl(f func(fd uintptr) error) error {
+	return p.waitControl(f)
+}
+
+// RemoveStaleLock removes the UUCP lock file at path if it is still the stale one.
+func RemoveStaleLock(path string, stale os.FileInfo) error {
+	return removeStaleLock(path, path+".moved", stale)
+}
+
+// WatchModemLinesWith starts a watch that reads the levels with get,
+// so the watch can be tested on a pty, which has no modem lines.
+func (p *Port) WatchModemLinesWith(ctx context.Context, get func() (ModemStatus, error)) (<-chan ModemEvent, error) {
+	return p.watchModemLines(ctx, get)
+}
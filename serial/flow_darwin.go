@@ -0,0 +1,20 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"time"
+)
+
+// All flow control modes are done by the driver on macOS.
+func (p *serialPort) setupFlowControl(_ OpenOptions) error {
+	return nil
+}
+
+func (p *serialPort) writeChunk(_ context.Context, chunk []byte, _ time.Time, _ uint64) (int, error) {
+	return p.File.Write(chunk)
+}
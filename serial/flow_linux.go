@@ -0,0 +1,78 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// The number of bytes kept in the output queue with DTR/DSR flow control.
+// It bounds the amount of data sent after DSR drops.
+const dsrFlowQueue = 64
+
+// Sets up the user space part of flow control, see `OpenOptions.FlowControl`.
+// Only the output is gated by DSR, DTR is just asserted.
+func (p *serialPort) setupFlowControl(options OpenOptions) error {
+	p.dsrFlow = options.flowControl() == FLOW_CONTROL_DTRDSR
+	if p.dsrFlow {
+		return p.SetDTR(true)
+	}
+	return nil
+}
+
+// Writes the chunk or a part of it.
+// With DTR/DSR flow control the data is passed to the driver only while DSR is asserted.
+func (p *serialPort) writeChunk(ctx context.Context, chunk []byte, deadline time.Time, gen uint64) (int, error) {
+	if !p.dsrFlow {
+		return p.File.Write(chunk)
+	}
+	room, err := p.waitDSR(ctx, deadline, gen)
+	if err != nil {
+		return 0, err
+	}
+	if len(chunk) > room {
+		chunk = chunk[:room]
+	}
+	return p.File.Write(chunk)
+}
+
+// Waits until DSR is asserted and the output queue has room, which is returned.
+// Returns os.ErrDeadlineExceeded when the deadline passes or is changed first.
+func (p *serialPort) waitDSR(ctx context.Context, deadline time.Time, gen uint64) (int, error) {
+	var ticker *time.Ticker
+	for {
+		status, err := p.GetModemStatus()
+		if err != nil {
+			return 0, err
+		}
+		if status.Has(MODEM_DSR) {
+			queued, err := p.OutputWaiting()
+			if err != nil {
+				return 0, err
+			}
+			if queued < dsrFlowQueue {
+				return dsrFlowQueue - queued, nil
+			}
+		}
+		if expired(deadline) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		if _, g := p.userWriteDeadline(); g != gen {
+			return 0, os.ErrDeadlineExceeded // the deadline has been changed
+		}
+		if ticker == nil {
+			ticker = time.NewTicker(drainPollInterval)
+			defer ticker.Stop()
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Calls f with the file descriptor of the port.
+// The descriptor stays valid until f returns, even if the port is being closed
+// by another goroutine.
+func (p *serialPort) control(f func(fd uintptr) error) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	rc, err := p.File.SyscallConn()
+	if err != nil {
+		return p.wrapError(err)
+	}
+	var ferr error
+	if err := rc.Control(func(fd uintptr) { ferr = f(fd) }); err != nil {
+		return p.wrapError(err)
+	}
+	return ferr
+}
+
+// Calls f with a duplicate of the file descriptor of the port, for the ioctls
+// that wait for the output to drain (tcdrain, TCSETSW2). They can't be interrupted,
+// and with the output stopped by flow control `control` would keep Close waiting.
+// The duplicate keeps the device open until f returns.
+func (p *serialPort) waitControl(f func(fd uintptr) error) error {
+	var dup int
+	err := p.control(func(fd uintptr) error {
+		var err error
+		dup, err = unix.FcntlInt(fd, unix.F_DUPFD_CLOEXEC, 0)
+		if err != nil {
+			return ioError(os.NewSyscallError("fcntl", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dup)
+
+	for {
+		// The wait is broken by signals, including the preemption signals of the Go runtime.
+		if err := f(uintptr(dup)); !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
+
+// Makes an ioctl syscall and checks the result the same way as the open code does.
+func ioctl(fd, req, arg uintptr) error {
+	r, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg)
+	if errno != 0 {
+		return ioError(os.NewSyscallError("SYS_IOCTL", errno))
+	}
+	if r != 0 {
+		return ioctlResultError(r)
+	}
+	return nil
+}
+
+// Error of an ioctl that has failed without errno, which drivers should not do.
+// It is a *os.SyscallError like the other ioctl errors.
+func ioctlResultError(r uintptr) error {
+	return os.NewSyscallError("SYS_IOCTL", fmt.Errorf("unexpected result %d", int(r)))
+}
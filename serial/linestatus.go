@@ -0,0 +1,176 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineError is a bitset of receive errors of a single byte.
+type LineError uint8
+
+const (
+	LINE_ERROR_PARITY  LineError = 1 << iota // The byte was received with a parity error.
+	LINE_ERROR_FRAMING                       // The byte was received without a valid stop bit.
+	LINE_ERROR_BREAK                         // A break condition; the byte itself is 0.
+)
+
+var lineErrorNames = []struct {
+	err  LineError
+	name string
+}{
+	{LINE_ERROR_PARITY, "parity"},
+	{LINE_ERROR_FRAMING, "framing"},
+	{LINE_ERROR_BREAK, "break"},
+}
+
+// String returns error names separated by "|", e.g. "parity|framing".
+func (e LineError) String() string {
+	var names []string
+	for _, l := range lineErrorNames {
+		if e&l.err != 0 {
+			names = append(names, l.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// LineStatusError is returned by Read when `OpenOptions.ReportLineErrors` is on
+// and some of the returned bytes were received with errors or some data was lost.
+// Use ReadWithStatus to get the errors of every byte.
+type LineStatusError struct {
+	Offset int       // Index of the first byte with an error in the returned data, -1 if none.
+	Errors LineError // Errors of that byte.
+	Count  int       // Number of bytes with errors in the returned data.
+
+	// Number of overruns counted by the driver since the previous read (Linux only).
+	// The lost data has no position in the input, so it is not tied to a byte.
+	Overruns int
+}
+
+func (e *LineStatusError) Error() string {
+	if e.Count == 0 {
+		return fmt.Sprintf("line error: %d overruns", e.Overruns)
+	}
+	msg := fmt.Sprintf("line error at byte %d: %s (%d bytes with errors)", e.Offset, e.Errors, e.Count)
+	if e.Overruns > 0 {
+		msg += fmt.Sprintf(", %d overruns", e.Overruns)
+	}
+	return msg
+}
+
+// Returns a LineStatusError for the first n status entries and the overruns,
+// or nil if there are no errors.
+func lineStatusError(status []LineError, n int, overruns int) error {
+	result := &LineStatusError{Offset: -1, Overruns: overruns}
+	for i, s := range status[:n] {
+		if s == 0 {
+			continue
+		}
+		if result.Count == 0 {
+			result.Offset, result.Errors = i, s
+		}
+		result.Count++
+	}
+	if result.Count == 0 && result.Overruns == 0 {
+		return nil
+	}
+	return result
+}
+
+// Decodes the input marked by termios PARMRK:
+//
+//	\377 \377   - a received 0xFF byte,
+//	\377 \0 X   - the byte X received with a parity or framing error,
+//	\377 \0 \0  - a break (or a 0 byte received with an error).
+//
+// A mark may be split between reads, so the decoder keeps its state.
+type parmrkDecoder struct {
+	state int // number of mark bytes seen: 0, 1 (\377) or 2 (\377 \0)
+}
+
+// Decodes buf in place and fills status for each decoded byte.
+// Returns the number of decoded bytes.
+func (d *parmrkDecoder) decode(buf []byte, status []LineError) int {
+	n := 0
+	put := func(b byte, s LineError) {
+		buf[n] = b
+		status[n] = s
+		n++
+	}
+	for i := 0; i < len(buf); i++ {
+		b := buf[i]
+		switch d.state {
+		case 0:
+			if b == 0xFF {
+				d.state = 1
+			} else {
+				put(b, 0)
+			}
+		case 1:
+			switch b {
+			case 0xFF:
+				put(0xFF, 0)
+				d.state = 0
+			case 0:
+				d.state = 2
+			default:
+				// Not a mark, deliver the bytes as is.
+				put(0xFF, 0)
+				d.state = 0
+				i--
+			}
+		case 2:
+			if b == 0 {
+				put(0, LINE_ERROR_BREAK)
+			} else {
+				put(b, LINE_ERROR_PARITY|LINE_ERROR_FRAMING)
+			}
+			d.state = 0
+		}
+	}
+	return n
+}
+
+// Changes of the driver error counters between two reads.
+type lineErrorCounts struct {
+	parity, frame, brk, overrun int
+}
+
+// PARMRK does not tell parity errors from framing errors,
+// nor a break from a 0 byte with an error.
+// Where the driver error counters are available, they resolve these cases.
+// Overruns are not marked at all, so they are counted separately.
+func refineLineErrors(status []LineError, delta lineErrorCounts) {
+	badBytes := delta.parity > 0 || delta.frame > 0
+	for i, s := range status {
+		if s&LINE_ERROR_BREAK != 0 && delta.brk == 0 && badBytes {
+			s = LINE_ERROR_PARITY | LINE_ERROR_FRAMING
+		}
+		if s&(LINE_ERROR_PARITY|LINE_ERROR_FRAMING) != 0 {
+			switch {
+			case delta.frame == 0 && delta.parity > 0:
+				s &^= LINE_ERROR_FRAMING
+			case delta.parity == 0 && delta.frame > 0:
+				s &^= LINE_ERROR_PARITY
+			}
+		}
+		status[i] = s
+	}
+}
+
+func (c lineErrorCounts) sub(prev lineErrorCounts) lineErrorCounts {
+	return lineErrorCounts{
+		parity:  c.parity - prev.parity,
+		frame:   c.frame - prev.frame,
+		brk:     c.brk - prev.brk,
+		overrun: c.overrun - prev.overrun,
+	}
+}
@@ -0,0 +1,11 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// OS X has no driver error counters, so the errors are taken from PARMRK marks only.
+func (p *serialPort) lineErrorCounts() (lineErrorCounts, error) {
+	return lineErrorCounts{}, ErrNotImplementedOnOS
+}
@@ -0,0 +1,20 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Reads the driver error counters with TIOCGICOUNT.
+func (p *serialPort) lineErrorCounts() (lineErrorCounts, error) {
+	c, err := p.Counters()
+	if err != nil {
+		return lineErrorCounts{}, err
+	}
+	return lineErrorCounts{
+		parity:  int(c.Parity),
+		frame:   int(c.Frame),
+		brk:     int(c.Break),
+		overrun: int(c.Overrun + c.BufferOverrun),
+	}, nil
+}
@@ -0,0 +1,88 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// A pty can't produce line errors, but PARMRK still escapes 0xFF bytes,
+// which must be decoded transparently.
+func TestLinuxReadWithStatus(t *testing.T) {
+	master, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.ReportLineErrors = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	data := []byte{1, 0xFF, 0, 0xFF, 2}
+	master.Write(data)
+	time.Sleep(time.Millisecond * 20)
+
+	buf := make([]byte, 16)
+	status := make([]serial.LineError, len(buf))
+	n, err := port.ReadWithStatus(buf, status)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	if !bytes.Equal(buf[:n], data) {
+		t.Errorf("expect %v, got %v", data, buf[:n])
+	}
+	for i, s := range status[:n] {
+		if s != 0 {
+			t.Errorf("expect no errors, got %s at %d", s, i)
+		}
+	}
+}
+
+func TestLinuxReadWithStatusDisabled(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	_, err := port.ReadWithStatus(make([]byte, 1), make([]serial.LineError, 1))
+	if !errors.Is(err, serial.ErrLineErrorsDisabled) {
+		t.Errorf("expect ErrLineErrorsDisabled, got %v", err)
+	}
+}
+
+// An escaped 0xFF takes two bytes of the input, but MinimumReadSize
+// counts the decoded bytes.
+func TestLinuxReadWithStatusMinimumReadSize(t *testing.T) {
+	master, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.ReportLineErrors = true
+	opt.MinimumReadSize = 2
+	opt.InterCharacterTimeout = 0
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	go func() {
+		master.Write([]byte{0xFF})
+		time.Sleep(time.Millisecond * 50)
+		master.Write([]byte{1})
+	}()
+
+	buf := make([]byte, 16)
+	n, err := port.Read(buf)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	if expect := []byte{0xFF, 1}; !bytes.Equal(buf[:n], expect) {
+		t.Errorf("expect %v, got %v", expect, buf[:n])
+	}
+}
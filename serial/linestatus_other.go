@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+func (p *serialPort) ReadWithStatus(_ []byte, _ []LineError) (int, error) {
+	return 0, ErrNotImplementedOnOS
+}
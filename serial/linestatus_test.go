@@ -0,0 +1,105 @@
+package serial
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParmrkDecoder(t *testing.T) {
+	testCases := []struct {
+		name   string
+		chunks [][]byte
+		data   []byte
+		status []LineError
+	}{
+		{"plain", [][]byte{{1, 2, 3}}, []byte{1, 2, 3}, []LineError{0, 0, 0}},
+		{"escaped 0xFF", [][]byte{{1, 0xFF, 0xFF, 2}}, []byte{1, 0xFF, 2}, []LineError{0, 0, 0}},
+		{"bad byte", [][]byte{{1, 0xFF, 0, 0x55}}, []byte{1, 0x55},
+			[]LineError{0, LINE_ERROR_PARITY | LINE_ERROR_FRAMING}},
+		{"break", [][]byte{{0xFF, 0, 0, 7}}, []byte{0, 7}, []LineError{LINE_ERROR_BREAK, 0}},
+		{"mark split between reads", [][]byte{{1, 0xFF}, {0}, {0x55, 2}}, []byte{1, 0x55, 2},
+			[]LineError{0, LINE_ERROR_PARITY | LINE_ERROR_FRAMING, 0}},
+		{"unexpected byte after 0xFF", [][]byte{{0xFF, 3}}, []byte{0xFF, 3}, []LineError{0, 0}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var d parmrkDecoder
+			var data []byte
+			var status []LineError
+			for _, chunk := range testCase.chunks {
+				buf := append([]byte(nil), chunk...)
+				st := make([]LineError, len(buf))
+				n := d.decode(buf, st)
+				data = append(data, buf[:n]...)
+				status = append(status, st[:n]...)
+			}
+			if !bytes.Equal(data, testCase.data) {
+				t.Errorf("expected data %v, but got %v", testCase.data, data)
+			}
+			if !reflect.DeepEqual(status, testCase.status) {
+				t.Errorf("expected status %v, but got %v", testCase.status, status)
+			}
+		})
+	}
+}
+
+func TestRefineLineErrors(t *testing.T) {
+	const bad = LINE_ERROR_PARITY | LINE_ERROR_FRAMING
+
+	testCases := []struct {
+		name   string
+		status []LineError
+		delta  lineErrorCounts
+		expect []LineError
+	}{
+		{"parity only", []LineError{0, bad}, lineErrorCounts{parity: 1}, []LineError{0, LINE_ERROR_PARITY}},
+		{"framing only", []LineError{bad}, lineErrorCounts{frame: 1}, []LineError{LINE_ERROR_FRAMING}},
+		{"both", []LineError{bad, bad}, lineErrorCounts{parity: 1, frame: 1}, []LineError{bad, bad}},
+		{"real break", []LineError{LINE_ERROR_BREAK}, lineErrorCounts{brk: 1}, []LineError{LINE_ERROR_BREAK}},
+		{"zero byte with parity error", []LineError{LINE_ERROR_BREAK}, lineErrorCounts{parity: 1}, []LineError{bad &^ LINE_ERROR_FRAMING}},
+		{"overrun is not put on a byte", []LineError{0, bad}, lineErrorCounts{parity: 1, overrun: 3},
+			[]LineError{0, LINE_ERROR_PARITY}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			refineLineErrors(testCase.status, testCase.delta)
+			if !reflect.DeepEqual(testCase.status, testCase.expect) {
+				t.Errorf("expected status %v, but got %v", testCase.expect, testCase.status)
+			}
+		})
+	}
+}
+
+func TestLineStatusError(t *testing.T) {
+	const bad = LINE_ERROR_PARITY
+
+	testCases := []struct {
+		name     string
+		status   []LineError
+		overruns int
+		expect   *LineStatusError
+	}{
+		{"no errors", []LineError{0, 0}, 0, nil},
+		{"bad bytes", []LineError{0, bad, bad}, 0, &LineStatusError{Offset: 1, Errors: bad, Count: 2}},
+		{"overruns only", []LineError{0, 0}, 2, &LineStatusError{Offset: -1, Overruns: 2}},
+		{"bad bytes and overruns", []LineError{bad}, 1, &LineStatusError{Offset: 0, Errors: bad, Count: 1, Overruns: 1}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := lineStatusError(testCase.status, len(testCase.status), testCase.overruns)
+			if testCase.expect == nil {
+				if err != nil {
+					t.Errorf("expected no error, but got %v", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(err, testCase.expect) {
+				t.Errorf("expected %+v, but got %+v", testCase.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ReadWithStatus reads data like Read does and reports the receive errors
+// of each returned byte in status. The status slice must be at least as long as buf.
+// It requires `OpenOptions.ReportLineErrors`.
+//
+// Overruns counted by the driver are not tied to a byte, so they are reported
+// by a *LineStatusError with the Overruns count, along with the data.
+func (p *serialPort) ReadWithStatus(buf []byte, status []LineError) (int, error) {
+	n, overruns, err := p.readWithStatus(context.Background(), buf, status)
+	if err == nil && overruns > 0 {
+		err = lineStatusError(status, n, overruns)
+	}
+	return n, p.wrapError(err)
+}
+
+// Returns the number of decoded bytes and the number of overruns.
+func (p *serialPort) readWithStatus(ctx context.Context, buf []byte, status []LineError) (int, int, error) {
+	if p == nil || p.File == nil {
+		return 0, 0, ErrInvalidOrNilPort
+	}
+	if !p.markErrors {
+		return 0, 0, ErrLineErrorsDisabled
+	}
+	if len(status) < len(buf) {
+		return 0, 0, errors.New("status buffer is shorter than data buffer")
+	}
+	if len(buf) == 0 {
+		return 0, 0, nil
+	}
+
+	// The timers are shared by all reads below, so that the partial marks
+	// don't restart them. MinimumReadSize counts the decoded bytes, not the marks,
+	// so each read asks only for the bytes that are still missing.
+	policy := p.readPolicy()
+	timers := readTimers{start: time.Now()}
+	var decoded, overruns int
+	for {
+		part := policy
+		part.eofOnTimeout = false
+		if policy.minBytes > 0 {
+			part.minBytes = policy.minBytes - decoded
+		}
+		n, err := p.readTimed(ctx, buf[decoded:], part, &timers)
+		m := p.decoder.decode(buf[decoded:decoded+n], status[decoded:])
+		if m > 0 {
+			overruns += p.refineLineErrors(status[decoded : decoded+m])
+		}
+		decoded += m
+		if err != nil {
+			return decoded, overruns, err
+		}
+		if decoded == len(buf) || (policy.minBytes > 0 && decoded >= policy.minBytes) ||
+			expired(policy.deadline(timers.start, timers.lastByte)) {
+			break
+		}
+	}
+
+	if decoded == 0 && policy.eofOnTimeout {
+		return 0, overruns, io.EOF
+	}
+	return decoded, overruns, nil
+}
+
+// Takes the initial snapshot of the driver error counters.
+func (p *serialPort) initLineErrorCounts() {
+	if !p.markErrors {
+		return
+	}
+	counts, err := p.lineErrorCounts()
+	if err != nil {
+		p.countersFailed = true
+		return
+	}
+	p.lastCounts = counts
+}
+
+// Refines the errors with the driver error counters and returns the number of overruns.
+func (p *serialPort) refineLineErrors(status []LineError) int {
+	if p.countersFailed {
+		return 0
+	}
+	counts, err := p.lineErrorCounts()
+	if err != nil {
+		p.countersFailed = true
+		return 0
+	}
+	delta := counts.sub(p.lastCounts)
+	refineLineErrors(status, delta)
+	p.lastCounts = counts
+	return delta.overrun
+}
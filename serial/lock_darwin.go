@@ -0,0 +1,11 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// OS X has no way to find the owner of a flock.
+func (p *serialPort) flockOwner() int {
+	return 0
+}
@@ -0,0 +1,49 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Looks up the process holding a flock on the port in /proc/locks.
+// Returns 0 if it can't be found.
+func (p *serialPort) flockOwner() int {
+	var st unix.Stat_t
+	err := p.control(func(fd uintptr) error {
+		return unix.Fstat(int(fd), &st)
+	})
+	if err != nil {
+		return 0
+	}
+
+	f, err := os.Open("/proc/locks")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	// 1: FLOCK  ADVISORY  WRITE 1234 00:05:1045 0 EOF
+	// The device is major:minor of the filesystem in hex, then the inode.
+	id := fmt.Sprintf("%02x:%02x:%d", unix.Major(st.Dev), unix.Minor(st.Dev), st.Ino)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[1] != "FLOCK" || fields[5] != id {
+			continue
+		}
+		if pid, err := strconv.Atoi(fields[4]); err == nil {
+			return pid
+		}
+	}
+	return 0
+}
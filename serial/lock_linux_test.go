@@ -0,0 +1,162 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxExclusive(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.Exclusive = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	// Root is not stopped by TIOCEXCL, but the flock works for everyone.
+	var busy *serial.PortBusyError
+	_, err = serial.Open(opt)
+	if !errors.Is(err, serial.ErrPortBusy) || !errors.As(err, &busy) {
+		t.Fatalf("expect PortBusyError, got %v", err)
+	}
+	if busy.PID != 0 && busy.PID != os.Getpid() {
+		t.Errorf("expect owner PID %d, got %d", os.Getpid(), busy.PID)
+	}
+
+	port.Close()
+	port, err = serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open after close error: %s", err)
+	}
+	port.Close()
+}
+
+func TestLinuxUUCPLock(t *testing.T) {
+	_, name := openPty(t)
+	useLockDir(t)
+	lockFile := filepath.Join(serial.UUCPLockDir, "LCK.."+filepath.Base(name))
+
+	opt := newPtyOpenOptions(name)
+	opt.UUCPLock = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	if pid := readPID(t, lockFile); pid != os.Getpid() {
+		t.Errorf("expect lock file with PID %d, got %d", os.Getpid(), pid)
+	}
+
+	var busy *serial.PortBusyError
+	_, err = serial.Open(opt)
+	if !errors.As(err, &busy) || busy.PID != os.Getpid() {
+		t.Fatalf("expect PortBusyError with PID %d, got %v", os.Getpid(), err)
+	}
+
+	port.Close()
+	if _, err := os.Stat(lockFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect lock file to be removed, got %v", err)
+	}
+}
+
+func TestLinuxUUCPLockStale(t *testing.T) {
+	_, name := openPty(t)
+	useLockDir(t)
+	lockFile := filepath.Join(serial.UUCPLockDir, "LCK.."+filepath.Base(name))
+
+	// PIDs can't be that large, so nobody owns the lock.
+	if err := os.WriteFile(lockFile, []byte(fmt.Sprintf("%10d\n", 1<<30)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opt := newPtyOpenOptions(name)
+	opt.UUCPLock = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	if pid := readPID(t, lockFile); pid != os.Getpid() {
+		t.Errorf("expect lock file with PID %d, got %d", os.Getpid(), pid)
+	}
+}
+
+// Two processes may find the same stale lock. Once one of them has replaced it
+// with its own lock, the other one must not remove that.
+func TestLinuxUUCPLockStaleRace(t *testing.T) {
+	useLockDir(t)
+	lockFile := filepath.Join(serial.UUCPLockDir, "LCK..ttyS99")
+	stale := fmt.Sprintf("%10d\n", 1<<30)
+	fresh := fmt.Sprintf("%10d\n", os.Getpid())
+
+	if err := os.WriteFile(lockFile, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other process takes over the stale lock.
+	if err := os.Remove(lockFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockFile, []byte(fresh), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := serial.RemoveStaleLock(lockFile, info); err != nil {
+		t.Fatalf("remove error: %s", err)
+	}
+	if pid := readPID(t, lockFile); pid != os.Getpid() {
+		t.Errorf("expect the fresh lock with PID %d to be kept, got %d", os.Getpid(), pid)
+	}
+
+	// The stale lock itself is removed.
+	if err := os.WriteFile(lockFile, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = os.Stat(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := serial.RemoveStaleLock(lockFile, info); err != nil {
+		t.Fatalf("remove error: %s", err)
+	}
+	if _, err := os.Stat(lockFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expect the stale lock to be removed, got %v", err)
+	}
+}
+
+func useLockDir(t *testing.T) {
+	orig := serial.UUCPLockDir
+	serial.UUCPLockDir = t.TempDir()
+	t.Cleanup(func() { serial.UUCPLockDir = orig })
+}
+
+func readPID(t *testing.T, name string) int {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
@@ -0,0 +1,181 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Directory of UUCP-style lock files, see `OpenOptions.UUCPLock`.
+var UUCPLockDir = "/var/lock"
+
+// Takes the port for exclusive use with flock and TIOCEXCL.
+// TIOCEXCL makes other open calls fail with EBUSY (except for root),
+// flock is honored by other programs that lock the port the same way.
+func (p *serialPort) lockExclusive(name string) error {
+	err := p.control(func(fd uintptr) error {
+		return unix.Flock(int(fd), unix.LOCK_EX|unix.LOCK_NB)
+	})
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return &PortBusyError{Port: name, PID: p.flockOwner()}
+	}
+	if err != nil {
+		return os.NewSyscallError("flock", err)
+	}
+
+	err = p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCEXCL, 0)
+	})
+	if err != nil {
+		return err
+	}
+	p.exclusive = true
+	return nil
+}
+
+// Gives up the exclusive use. The flock is released explicitly, since the file
+// may be kept open a bit longer by a pending call (see `waitControl`).
+func (p *serialPort) unlockExclusive() error {
+	if !p.exclusive {
+		return nil
+	}
+	p.exclusive = false
+	return p.control(func(fd uintptr) error {
+		if err := unix.Flock(int(fd), unix.LOCK_UN); err != nil {
+			return os.NewSyscallError("flock", err)
+		}
+		return ioctl(fd, unix.TIOCNXCL, 0)
+	})
+}
+
+// uucpLock is a lock file like /var/lock/LCK..ttyUSB0 containing the PID of the owner.
+// Such files are used by minicom, pppd, ModemManager and other programs.
+type uucpLock struct {
+	path string
+}
+
+// Creates the lock file for the device in dir.
+// A lock file left by a process that no longer exists is removed.
+func acquireUUCPLock(dir, device string) (*uucpLock, error) {
+	// Locks are named after the real device, not after a symlink to it.
+	if real, err := filepath.EvalSymlinks(device); err == nil {
+		device = real
+	}
+	path := filepath.Join(dir, "LCK.."+filepath.Base(device))
+
+	// The PID is written to a temporary file which is then linked to the lock name,
+	// so nobody can see a lock file without the PID.
+	tmp, err := os.CreateTemp(dir, "LTMP.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = fmt.Fprintf(tmp, "%10d\n", os.Getpid())
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := os.Link(tmp.Name(), path)
+		if err == nil {
+			return &uucpLock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		pid, info := readLockOwner(path)
+		if lockOwnerAlive(pid) || attempt > 0 {
+			return nil, &PortBusyError{Port: device, PID: pid}
+		}
+		if info == nil {
+			continue // removed by its owner meanwhile
+		}
+		if err := removeStaleLock(path, tmp.Name()+".stale", info); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Removes the stale lock file, which has been checked as stale.
+// Another process may have removed it and linked its own lock in the meantime,
+// so the file is moved away first and put back if it is not the stale one.
+// The inode of a removed file may be reused at once, so the PID is checked again too.
+func removeStaleLock(path, moved string, stale os.FileInfo) error {
+	if err := os.Rename(path, moved); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // removed by another process
+		}
+		return err
+	}
+	var err error
+	if pid, info := readLockOwner(moved); info == nil || !os.SameFile(info, stale) || lockOwnerAlive(pid) {
+		err = os.Link(moved, path)
+	}
+	return errors.Join(err, os.Remove(moved))
+}
+
+func lockOwnerAlive(pid int) bool {
+	return pid > 0 && (pid == os.Getpid() || processExists(pid))
+}
+
+// Removes the lock file if it is still ours.
+func (l *uucpLock) release() error {
+	if l == nil {
+		return nil
+	}
+	if readLockPID(l.path) != os.Getpid() {
+		return nil
+	}
+	return os.Remove(l.path)
+}
+
+// Reads the ASCII PID of a lock file. Returns 0 if the PID can't be read.
+func readLockPID(path string) int {
+	pid, _ := readLockOwner(path)
+	return pid
+}
+
+// Reads the ASCII PID of a lock file along with the file info, which tells
+// that the file has not been replaced later. The info is nil if the file can't be read.
+func readLockOwner(path string) (int, os.FileInfo) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, info
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, info
+	}
+	return pid, info
+}
+
+func processExists(pid int) bool {
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
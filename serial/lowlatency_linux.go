@@ -0,0 +1,142 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Flag of serial_struct from linux/tty_flags.h
+const aSYNC_LOW_LATENCY = 1 << 13
+
+// Type from linux/serial.h
+type serial_struct struct {
+	typ             int32
+	line            int32
+	port            uint32
+	irq             int32
+	flags           int32
+	xmit_fifo_size  int32
+	custom_divisor  int32
+	baud_base       int32
+	close_delay     uint16
+	io_type         byte
+	reserved_char   [1]byte
+	hub6            int32
+	closing_wait    uint16
+	closing_wait2   uint16
+	iomem_base      uintptr
+	iomem_reg_shift uint16
+	port_high       uint32
+	iomap_base      uintptr
+}
+
+// Settings changed by enableLowLatency, to be restored on Close.
+type lowLatencyBackup struct {
+	flagSet   bool   // ASYNC_LOW_LATENCY has been set
+	timerPath string // latency_timer that has been changed
+	timer     []byte // previous value of latency_timer
+}
+
+func (p *serialPort) getSerial() (*serial_struct, error) {
+	ss := &serial_struct{}
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCGSERIAL, uintptr(unsafe.Pointer(ss)))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (p *serialPort) setSerial(ss *serial_struct) error {
+	return p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCSSERIAL, uintptr(unsafe.Pointer(ss)))
+	})
+}
+
+// Returns the latency_timer attribute of USB serial adapters like ftdi_sio,
+// or an empty string if the port has none.
+func (p *serialPort) latencyTimerPath() string {
+	name := p.File.Name()
+	if real, err := filepath.EvalSymlinks(name); err == nil {
+		name = real
+	}
+	path := filepath.Join(defaultSysfs.sys, "class", "tty", filepath.Base(name), "device", "latency_timer")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Sets ASYNC_LOW_LATENCY and a 1 ms latency timer where possible.
+// It is done on a best effort basis, use LowLatency to check the result.
+func (p *serialPort) enableLowLatency() {
+	backup := &lowLatencyBackup{}
+	p.lowLatency = backup
+
+	if ss, err := p.getSerial(); err == nil && ss.flags&aSYNC_LOW_LATENCY == 0 {
+		ss.flags |= aSYNC_LOW_LATENCY
+		backup.flagSet = p.setSerial(ss) == nil
+	}
+
+	if path := p.latencyTimerPath(); path != "" {
+		if timer, err := os.ReadFile(path); err == nil && string(timer) != "1\n" {
+			if os.WriteFile(path, []byte("1"), 0) == nil {
+				backup.timerPath = path
+				backup.timer = timer
+			}
+		}
+	}
+}
+
+// Restores the settings changed by enableLowLatency.
+func (p *serialPort) restoreLowLatency() error {
+	backup := p.lowLatency
+	if backup == nil {
+		return nil
+	}
+	p.lowLatency = nil
+
+	var err error
+	if backup.flagSet {
+		ss, getErr := p.getSerial()
+		if getErr == nil {
+			ss.flags &^= aSYNC_LOW_LATENCY
+			getErr = p.setSerial(ss)
+		}
+		err = getErr
+	}
+	if backup.timerPath != "" {
+		err = errors.Join(err, os.WriteFile(backup.timerPath, backup.timer, 0))
+	}
+	return err
+}
+
+// LowLatency tells whether the port is in low latency mode: ASYNC_LOW_LATENCY is set
+// and, for USB adapters with a latency timer (e.g. ftdi_sio), the timer is 1 ms.
+// See `OpenOptions.LowLatency`.
+func (p *serialPort) LowLatency() (bool, error) {
+	if p == nil || p.File == nil {
+		return false, ErrInvalidOrNilPort
+	}
+	ss, err := p.getSerial()
+	if err != nil {
+		return false, err
+	}
+	if ss.flags&aSYNC_LOW_LATENCY == 0 {
+		return false, nil
+	}
+	if path := p.latencyTimerPath(); path != "" {
+		return readAttr(filepath.Dir(path), "latency_timer") == "1", nil
+	}
+	return true, nil
+}
@@ -0,0 +1,32 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// A pty has no serial_struct, so the option must not break Open,
+// and the mode is reported as not in effect.
+func TestLinuxLowLatency(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.LowLatency = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	if ok, _ := port.LowLatency(); ok {
+		t.Errorf("expect low latency mode not to be in effect on a pty")
+	}
+	if err := port.Close(); err != nil {
+		t.Errorf("close error: %s", err)
+	}
+}
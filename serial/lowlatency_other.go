@@ -0,0 +1,17 @@
+//go:build !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Low latency mode is not supported on target OS.
+func (p *serialPort) LowLatency() (bool, error) {
+	return false, ErrNotImplementedOnOS
+}
+
+func (p *serialPort) restoreLowLatency() error {
+	return nil
+}
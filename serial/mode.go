@@ -0,0 +1,34 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Mode is the effective configuration of an open port, as reported by the OS.
+// It may differ from OpenOptions, e.g. when the driver rounds the baud rate.
+type Mode struct {
+	// Baud rates of both directions. They differ only if the driver supports split speeds.
+	InputBaudRate  uint
+	OutputBaudRate uint
+
+	DataBits   uint
+	StopBits   uint
+	ParityMode ParityMode
+
+	FlowControl FlowControl
+	XonChar     byte // used by FLOW_CONTROL_XONXOFF
+	XoffChar    byte // used by FLOW_CONTROL_XONXOFF
+
+	// Bytes are marked with their receive errors (PARMRK), see OpenOptions.ReportLineErrors.
+	ReportLineErrors bool
+
+	// Raw termios VMIN and VTIME (in tenths of a second) values.
+	// Read timeouts are emulated in user space, so these are normally 1 and 0.
+	// Always zero on Windows.
+	Vmin  uint8
+	Vtime uint8
+
+	// RS485 settings, Linux only.
+	RS485 RS485Config
+}
@@ -0,0 +1,70 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "unsafe"
+
+// Mode returns the effective configuration of the port, built from TIOCGETA.
+// RS485 is not supported on OS X, so the RS485 fields are always zero.
+func (p *serialPort) Mode() (Mode, error) {
+	if p == nil || p.File == nil {
+		return Mode{}, ErrInvalidOrNilPort
+	}
+
+	var t termios
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, kTIOCGETA, uintptr(unsafe.Pointer(&t)))
+	})
+	if err != nil {
+		return Mode{}, err
+	}
+
+	mode := Mode{
+		InputBaudRate:    uint(t.c_ispeed),
+		OutputBaudRate:   uint(t.c_ospeed),
+		StopBits:         1,
+		ReportLineErrors: t.c_iflag&kPARMRK != 0,
+		Vmin:             uint8(t.c_cc[kVMIN]),
+		Vtime:            uint8(t.c_cc[kVTIME]),
+	}
+
+	switch t.c_cflag & kCS8 {
+	case kCS5:
+		mode.DataBits = 5
+	case kCS6:
+		mode.DataBits = 6
+	case kCS7:
+		mode.DataBits = 7
+	case kCS8:
+		mode.DataBits = 8
+	}
+
+	if t.c_cflag&kCSTOPB != 0 {
+		mode.StopBits = 2
+	}
+
+	switch {
+	case t.c_cflag&kPARENB == 0:
+		mode.ParityMode = PARITY_NONE
+	case t.c_cflag&kPARODD != 0:
+		mode.ParityMode = PARITY_ODD
+	default:
+		mode.ParityMode = PARITY_EVEN
+	}
+
+	switch {
+	case t.c_cflag&kCRTSCTS != 0:
+		mode.FlowControl = FLOW_CONTROL_RTSCTS
+	case t.c_cflag&(kCDTR_IFLOW|kCDSR_OFLOW) != 0:
+		mode.FlowControl = FLOW_CONTROL_DTRDSR
+	case t.c_iflag&(kIXON|kIXOFF) != 0:
+		mode.FlowControl = FLOW_CONTROL_XONXOFF
+		mode.XonChar = byte(t.c_cc[kVSTART])
+		mode.XoffChar = byte(t.c_cc[kVSTOP])
+	}
+
+	return mode, nil
+}
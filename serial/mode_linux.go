@@ -0,0 +1,86 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Mode returns the effective configuration of the port,
+// built from TCGETS2 and TIOCGRS485.
+// RS485 fields are left zero if the driver does not support RS485.
+func (p *serialPort) Mode() (Mode, error) {
+	if p == nil || p.File == nil {
+		return Mode{}, ErrInvalidOrNilPort
+	}
+
+	t2, err := p.getTermios2()
+	if err != nil {
+		return Mode{}, err
+	}
+	mode := modeFromTermios2(t2)
+
+	if mode.FlowControl == FLOW_CONTROL_NONE && p.dsrFlow {
+		mode.FlowControl = FLOW_CONTROL_DTRDSR
+	}
+
+	mode.RS485, err = p.RS485()
+	if errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EINVAL) {
+		// No RS485 support in the driver.
+		err = nil
+	}
+	if err != nil {
+		return Mode{}, err
+	}
+
+	return mode, nil
+}
+
+func modeFromTermios2(t2 *termios2) Mode {
+	mode := Mode{
+		InputBaudRate:    uint(t2.c_ispeed),
+		OutputBaudRate:   uint(t2.c_ospeed),
+		StopBits:         1,
+		ParityMode:       parityFromFlags(t2.c_cflag),
+		ReportLineErrors: t2.c_iflag&syscall.PARMRK != 0,
+		Vmin:             uint8(t2.c_cc[syscall.VMIN]),
+		Vtime:            uint8(t2.c_cc[syscall.VTIME]),
+	}
+
+	// Zero input speed means "same as output", see termios(3).
+	if mode.InputBaudRate == 0 {
+		mode.InputBaudRate = mode.OutputBaudRate
+	}
+
+	switch t2.c_cflag & syscall.CSIZE {
+	case syscall.CS5:
+		mode.DataBits = 5
+	case syscall.CS6:
+		mode.DataBits = 6
+	case syscall.CS7:
+		mode.DataBits = 7
+	case syscall.CS8:
+		mode.DataBits = 8
+	}
+
+	if t2.c_cflag&syscall.CSTOPB != 0 {
+		mode.StopBits = 2
+	}
+
+	switch {
+	case t2.c_cflag&unix.CRTSCTS != 0:
+		mode.FlowControl = FLOW_CONTROL_RTSCTS
+	case t2.c_iflag&(syscall.IXON|syscall.IXOFF) != 0:
+		mode.FlowControl = FLOW_CONTROL_XONXOFF
+		mode.XonChar = byte(t2.c_cc[syscall.VSTART])
+		mode.XoffChar = byte(t2.c_cc[syscall.VSTOP])
+	}
+
+	return mode
+}
@@ -0,0 +1,44 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxMode(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.BaudRate = 57600
+	opt.StopBits = 2
+	opt.FlowControl = serial.FLOW_CONTROL_RTSCTS
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	mode, err := port.Mode()
+	if err != nil {
+		t.Fatalf("mode error: %s", err)
+	}
+	// A pty forces CS8 and no parity, and does not support RS485.
+	expected := serial.Mode{
+		InputBaudRate:  57600,
+		OutputBaudRate: 57600,
+		DataBits:       8,
+		StopBits:       2,
+		ParityMode:     serial.PARITY_NONE,
+		FlowControl:    serial.FLOW_CONTROL_RTSCTS,
+		Vmin:           1,
+	}
+	if mode != expected {
+		t.Errorf("expect %+v, got %+v", expected, mode)
+	}
+}
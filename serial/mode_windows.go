@@ -0,0 +1,50 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+// Mode returns the effective configuration of the port, built from GetCommState.
+// Windows has no split speeds, VMIN/VTIME or RS485 settings,
+// so these fields are left zero or equal to the output speed.
+// 1.5 stop bits are reported as StopBits = 0.
+func (p *serialPort) Mode() (Mode, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return Mode{}, ErrInvalidOrNilPort
+	}
+
+	params, err := getCommState(p.fd)
+	if err != nil {
+		return Mode{}, err
+	}
+
+	mode := Mode{
+		InputBaudRate:  uint(params.BaudRate),
+		OutputBaudRate: uint(params.BaudRate),
+		DataBits:       uint(params.ByteSize),
+		ParityMode:     ParityMode(params.Parity),
+	}
+
+	switch params.StopBits {
+	case 0: // ONESTOPBIT
+		mode.StopBits = 1
+	case 2: // TWOSTOPBITS
+		mode.StopBits = 2
+	}
+
+	switch {
+	case params.flags[0]&0x04 != 0: // fOutxCtsFlow
+		mode.FlowControl = FLOW_CONTROL_RTSCTS
+	case params.flags[0]&0x08 != 0: // fOutxDsrFlow
+		mode.FlowControl = FLOW_CONTROL_DTRDSR
+	case params.flags[1]&0x03 != 0: // fOutX, fInX
+		mode.FlowControl = FLOW_CONTROL_XONXOFF
+		mode.XonChar = params.XonChar
+		mode.XoffChar = params.XoffChar
+	}
+
+	return mode, nil
+}
@@ -0,0 +1,117 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ModemStatus is a bitset of modem control lines.
+// A set bit means that the line is asserted.
+type ModemStatus uint
+
+const (
+	// Inputs
+	MODEM_CTS ModemStatus = 1 << iota // Clear To Send
+	MODEM_DSR                         // Data Set Ready
+	MODEM_DCD                         // Data Carrier Detect
+	MODEM_RI                          // Ring Indicator
+
+	// Outputs
+	MODEM_DTR // Data Terminal Ready
+	MODEM_RTS // Request To Send
+
+	// All input lines
+	MODEM_INPUTS = MODEM_CTS | MODEM_DSR | MODEM_DCD | MODEM_RI
+)
+
+// Interval of modem status polling.
+const modemPollInterval = time.Millisecond * 10
+
+var modemLineNames = []struct {
+	line ModemStatus
+	name string
+}{
+	{MODEM_CTS, "CTS"},
+	{MODEM_DSR, "DSR"},
+	{MODEM_DCD, "DCD"},
+	{MODEM_RI, "RI"},
+	{MODEM_DTR, "DTR"},
+	{MODEM_RTS, "RTS"},
+}
+
+// Has checks whether all of the given lines are asserted.
+func (s ModemStatus) Has(lines ModemStatus) bool { return s&lines == lines }
+
+// String returns asserted lines separated by "|", e.g. "CTS|DSR".
+func (s ModemStatus) String() string {
+	var names []string
+	for _, l := range modemLineNames {
+		if s.Has(l.line) {
+			names = append(names, l.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// ModemEvent describes a change of one modem input line.
+type ModemEvent struct {
+	Line  ModemStatus // A single line, e.g. MODEM_DSR.
+	Level bool        // New level of the line, true if asserted.
+	Time  time.Time   // When the change was noticed.
+}
+
+// Sends an event for each changed input line.
+// The wait function blocks until the lines may have changed.
+// The returned channel is closed when ctx is done or when get or wait fail.
+// The optional release function is called after the channel is closed.
+func watchModemLines(
+	ctx context.Context,
+	get func() (ModemStatus, error),
+	wait func(ctx context.Context) error,
+	release func(),
+) (<-chan ModemEvent, error) {
+	prev, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	step := func(send func(ModemEvent) bool) bool {
+		status, err := get()
+		if err != nil {
+			return false
+		}
+		now := time.Now()
+		for _, l := range modemLineNames {
+			if l.line&MODEM_INPUTS == 0 || (status^prev)&l.line == 0 {
+				continue
+			}
+			if !send(ModemEvent{Line: l.line, Level: status.Has(l.line), Time: now}) {
+				return false
+			}
+		}
+		prev = status
+		return true
+	}
+	return runWatch(ctx, step, wait, release), nil
+}
+
+// Waits for the next poll of modem lines.
+func pollModemLines(ctx context.Context) error {
+	timer := time.NewTimer(modemPollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+func (p *serialPort) SetDTR(_ bool) error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) SetRTS(_ bool) error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) GetModemStatus() (ModemStatus, error) {
+	return 0, ErrNotImplementedOnOS
+}
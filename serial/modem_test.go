@@ -0,0 +1,74 @@
+package serial
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestModemStatusString(t *testing.T) {
+	testCases := []struct {
+		status ModemStatus
+		expect string
+	}{
+		{0, "none"},
+		{MODEM_CTS, "CTS"},
+		{MODEM_CTS | MODEM_DSR, "CTS|DSR"},
+		{MODEM_RI | MODEM_DCD | MODEM_RTS, "DCD|RI|RTS"},
+		{MODEM_DTR, "DTR"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expect, func(t *testing.T) {
+			if result := testCase.status.String(); result != testCase.expect {
+				t.Errorf("expected %q, but got %q", testCase.expect, result)
+			}
+		})
+	}
+}
+
+func TestWatchModemLines(t *testing.T) {
+	states := []ModemStatus{
+		MODEM_DSR | MODEM_DTR, // initial
+		MODEM_DSR | MODEM_DTR, // spurious wake up
+		MODEM_DSR | MODEM_CTS, // CTS up, DTR is an output and is ignored
+		MODEM_DCD | MODEM_RI,  // DSR and CTS down, DCD and RI up
+	}
+	get := func() (ModemStatus, error) {
+		if len(states) == 0 {
+			return 0, errors.New("no more states")
+		}
+		s := states[0]
+		states = states[1:]
+		return s, nil
+	}
+	wait := func(context.Context) error { return nil }
+	released := make(chan struct{})
+
+	events, err := watchModemLines(context.Background(), get, wait, func() { close(released) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []ModemEvent{
+		{Line: MODEM_CTS, Level: true},
+		{Line: MODEM_CTS, Level: false},
+		{Line: MODEM_DSR, Level: false},
+		{Line: MODEM_DCD, Level: true},
+		{Line: MODEM_RI, Level: true},
+	}
+	var got []ModemEvent
+	for e := range events {
+		if e.Time.IsZero() {
+			t.Errorf("expected event time to be set")
+		}
+		e.Time = time.Time{}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected events %v, but got %v", expect, got)
+	}
+	<-released
+}
@@ -0,0 +1,66 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Mapping between the TIOCM_* bits and ModemStatus.
+var tiocmLines = []struct {
+	tiocm int32
+	line  ModemStatus
+}{
+	{unix.TIOCM_CTS, MODEM_CTS},
+	{unix.TIOCM_DSR, MODEM_DSR},
+	{unix.TIOCM_CAR, MODEM_DCD},
+	{unix.TIOCM_RNG, MODEM_RI},
+	{unix.TIOCM_DTR, MODEM_DTR},
+	{unix.TIOCM_RTS, MODEM_RTS},
+}
+
+// SetDTR asserts (true) or clears (false) the DTR line.
+func (p *serialPort) SetDTR(on bool) error {
+	return p.setModemBits(unix.TIOCM_DTR, on)
+}
+
+// SetRTS asserts (true) or clears (false) the RTS line.
+// Note that the line is driven by the driver when RTS/CTS flow control or RS485 mode is on.
+func (p *serialPort) SetRTS(on bool) error {
+	return p.setModemBits(unix.TIOCM_RTS, on)
+}
+
+// GetModemStatus reads the state of modem control lines with TIOCMGET.
+func (p *serialPort) GetModemStatus() (ModemStatus, error) {
+	var bits int32
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCMGET, uintptr(unsafe.Pointer(&bits)))
+	})
+	if err != nil {
+		return 0, err
+	}
+	var status ModemStatus
+	for _, l := range tiocmLines {
+		if bits&l.tiocm != 0 {
+			status |= l.line
+		}
+	}
+	return status, nil
+}
+
+func (p *serialPort) setModemBits(bits int32, on bool) error {
+	req := uintptr(unix.TIOCMBIC)
+	if on {
+		req = unix.TIOCMBIS
+	}
+	return p.control(func(fd uintptr) error {
+		return ioctl(fd, req, uintptr(unsafe.Pointer(&bits)))
+	})
+}
@@ -0,0 +1,83 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// WatchModemLines reports changes of CTS, DSR, DCD and RI lines until ctx is done.
+//
+// The changes are waited for with TIOCMIWAIT. Drivers without it (ENOTTY or EINVAL)
+// are polled every `modemPollInterval`. Very short pulses may be missed,
+// since the levels are read after the change.
+//
+// TIOCMIWAIT can't be interrupted, so it is called by `waitControl`: when ctx is done
+// or the port is closed, the call is left to return on the next change of the lines.
+func (p *serialPort) WatchModemLines(ctx context.Context) (<-chan ModemEvent, error) {
+	return p.watchModemLines(ctx, p.GetModemStatus)
+}
+
+func (p *serialPort) watchModemLines(ctx context.Context, get func() (ModemStatus, error)) (<-chan ModemEvent, error) {
+	w := &modemLineWaiter{port: p}
+	// Taken before the levels are read, so a change right after that is not missed.
+	w.last, w.counted = w.counters()
+	return watchModemLines(ctx, get, w.wait, nil)
+}
+
+// Waits for a change of the modem lines.
+type modemLineWaiter struct {
+	port    *serialPort
+	last    Counters // the transition counters of TIOCGICOUNT, if counted
+	counted bool     // the driver provides TIOCGICOUNT
+	polling bool     // the driver has no TIOCMIWAIT
+}
+
+func (w *modemLineWaiter) wait(ctx context.Context) error {
+	if w.polling {
+		return pollModemLines(ctx)
+	}
+	// A change between the last read of the levels and TIOCMIWAIT would be missed,
+	// but it is seen by the counters.
+	if w.counted {
+		if c, ok := w.counters(); ok && modemLinesChanged(c, w.last) {
+			w.last = c
+			return nil
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.port.waitControl(func(fd uintptr) error {
+			return ioctl(fd, unix.TIOCMIWAIT, unix.TIOCM_CTS|unix.TIOCM_DSR|unix.TIOCM_CD|unix.TIOCM_RNG)
+		})
+	}()
+	select {
+	case err := <-done:
+		if errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.EINVAL) {
+			w.polling = true
+			return pollModemLines(ctx)
+		}
+		if w.counted {
+			w.last, _ = w.counters()
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *modemLineWaiter) counters() (Counters, bool) {
+	c, err := w.port.Counters()
+	return c, err == nil
+}
+
+func modemLinesChanged(a, b Counters) bool {
+	return a.CTS != b.CTS || a.DSR != b.DSR || a.RI != b.RI || a.DCD != b.DCD
+}
@@ -0,0 +1,77 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// A cancelled watch must not keep the port open, so it can be taken again after Close.
+func TestLinuxWatchModemLinesRelease(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.Exclusive = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// A pty has no modem lines, so the levels are made up.
+	get := func() (serial.ModemStatus, error) { return serial.MODEM_DSR, nil }
+	events, err := port.WatchModemLinesWith(ctx, get)
+	if err != nil {
+		t.Fatalf("watch error: %s", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	for range events {
+	}
+
+	if err := port.Close(); err != nil {
+		t.Fatalf("close error: %s", err)
+	}
+	port, err = serial.Open(opt)
+	if err != nil {
+		t.Fatalf("reopen error: %s", err)
+	}
+	port.Close()
+}
+
+// A pty has no TIOCMIWAIT, so the wait is made up. A pending wait keeps a duplicate
+// of the descriptor, which must not keep the port from being taken again after Close.
+func TestLinuxWatchModemLinesPendingWait(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.Exclusive = true
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	go port.WaitControl(func(uintptr) error {
+		<-release
+		return nil
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	if err := port.Close(); err != nil {
+		t.Fatalf("close error: %s", err)
+	}
+	port, err = serial.Open(opt)
+	if err != nil {
+		t.Fatalf("reopen error: %s", err)
+	}
+	port.Close()
+}
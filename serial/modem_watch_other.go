@@ -0,0 +1,16 @@
+//go:build !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "context"
+
+// WatchModemLines reports changes of CTS, DSR, DCD and RI lines until ctx is done.
+// On target OS the lines are polled every `modemPollInterval`.
+func (p *serialPort) WatchModemLines(ctx context.Context) (<-chan ModemEvent, error) {
+	return watchModemLines(ctx, p.GetModemStatus, pollModemLines, nil)
+}
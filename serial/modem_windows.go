@@ -0,0 +1,69 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+// EscapeCommFunction codes
+const (
+	wSETRTS = 3
+	wCLRRTS = 4
+	wSETDTR = 5
+	wCLRDTR = 6
+)
+
+// Mapping between the GetCommModemStatus bits and ModemStatus.
+var winModemLines = []struct {
+	ms   uint32
+	line ModemStatus
+}{
+	{0x0010, MODEM_CTS}, // MS_CTS_ON
+	{0x0020, MODEM_DSR}, // MS_DSR_ON
+	{0x0040, MODEM_RI},  // MS_RING_ON
+	{0x0080, MODEM_DCD}, // MS_RLSD_ON
+}
+
+// SetDTR asserts (true) or clears (false) the DTR line.
+func (p *serialPort) SetDTR(on bool) error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+	if on {
+		return escapeCommFunction(p.fd, wSETDTR)
+	}
+	return escapeCommFunction(p.fd, wCLRDTR)
+}
+
+// SetRTS asserts (true) or clears (false) the RTS line.
+// Note that the line is driven by the driver when RTS/CTS flow control is on.
+func (p *serialPort) SetRTS(on bool) error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+	if on {
+		return escapeCommFunction(p.fd, wSETRTS)
+	}
+	return escapeCommFunction(p.fd, wCLRRTS)
+}
+
+// GetModemStatus reads the state of modem input lines with GetCommModemStatus.
+// Windows does not report the state of DTR and RTS outputs.
+func (p *serialPort) GetModemStatus() (ModemStatus, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return 0, ErrInvalidOrNilPort
+	}
+	bits, err := getCommModemStatus(p.fd)
+	if err != nil {
+		return 0, err
+	}
+	var status ModemStatus
+	for _, l := range winModemLines {
+		if bits&l.ms != 0 {
+			status |= l.line
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,49 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "errors"
+
+// WriteMultidrop sends a message of the 9-bit multidrop addressing scheme:
+// the address byte with the 9th bit set, then the data bytes with the 9th bit cleared.
+//
+// The parity bit plays the role of the 9th bit, so the port should use 8 data bits.
+// The parity is switched to PARITY_MARK for the address and to PARITY_SPACE for the data.
+// Each switch waits until the previous bytes have been transmitted.
+// The original parity mode is restored after the data has been sent.
+//
+// Returns the number of data bytes written. If the address byte can't be written
+// within the write timeout, the data is not sent and ErrTimeout is returned.
+// On Linux the waits before the switches are bounded by the write timeout
+// and the write deadline too.
+func (p *serialPort) WriteMultidrop(address byte, data []byte) (int, error) {
+	return p.writeMultidrop(address, data, p.setParityDrained)
+}
+
+func (p *serialPort) writeMultidrop(address byte, data []byte, setParity func(ParityMode) error) (n int, err error) {
+	orig, err := p.parityMode()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = errors.Join(err, setParity(orig))
+	}()
+
+	if err := setParity(PARITY_MARK); err != nil {
+		return 0, err
+	}
+	// Data without its address would go to the previously addressed device.
+	if k, err := p.Write([]byte{address}); err != nil || k != 1 {
+		if err == nil {
+			err = ErrTimeout
+		}
+		return 0, err
+	}
+	if err := setParity(PARITY_SPACE); err != nil {
+		return 0, err
+	}
+	return p.Write(data)
+}
@@ -0,0 +1,51 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+func (p *serialPort) parityMode() (ParityMode, error) {
+	t2, err := p.getTermios2()
+	if err != nil {
+		return PARITY_NONE, err
+	}
+	return parityFromFlags(t2.c_cflag), nil
+}
+
+// Changes the parity mode once the output is drained.
+func (p *serialPort) setParityDrained(mode ParityMode) error {
+	return p.setParityAfter(mode, p.DrainContext)
+}
+
+// Changes the parity mode once drain returns. The drain is bounded by the write
+// timeout and the write deadline, and gives ErrTimeout when they expire.
+func (p *serialPort) setParityAfter(mode ParityMode, drain func(context.Context) error) error {
+	flags, err := parityFlags(mode)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := p.writeContext()
+	defer cancel()
+	if err := drain(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrTimeout
+		}
+		return err
+	}
+	t2, err := p.getTermios2()
+	if err != nil {
+		return err
+	}
+	t2.c_cflag &^= syscall.PARENB | syscall.PARODD | unix.CMSPAR
+	t2.c_cflag |= flags
+	return p.setTermios2(unix.TCSETS2, t2)
+}
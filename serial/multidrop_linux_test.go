@@ -0,0 +1,106 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// A pty does not transmit the parity bit, so this checks the byte stream only.
+func TestLinuxWriteMultidrop(t *testing.T) {
+	port, remote := openPtyPort(t)
+
+	n, err := port.WriteMultidrop(0x42, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("expect 3 data bytes written, got %d", n)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(remote, buf); err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	if !bytes.Equal(buf, []byte{0x42, 1, 2, 3}) {
+		t.Errorf("expect address and data bytes, got %v", buf)
+	}
+}
+
+// The output of a pty is never stopped, so the drain is made to wait
+// like with the output held by flow control.
+func TestLinuxWriteMultidropDrainTimeout(t *testing.T) {
+	stopped := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	testCases := []struct {
+		name string
+		set  func(port *serial.Port)
+	}{
+		{"write timeout", func(port *serial.Port) {
+			port.SetTimeouts(serial.Timeouts{WriteTotal: 20 * time.Millisecond})
+		}},
+		{"write deadline", func(port *serial.Port) {
+			port.SetWriteDeadline(time.Now().Add(20 * time.Millisecond))
+		}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			port, _ := openPtyPort(t)
+			testCase.set(port)
+
+			result := make(chan error, 1)
+			go func() {
+				n, err := port.WriteMultidropWith(0x42, []byte{1, 2, 3}, stopped)
+				if n != 0 {
+					err = fmt.Errorf("expect no data bytes written, got %d", n)
+				}
+				result <- err
+			}()
+			select {
+			case err := <-result:
+				if !errors.Is(err, serial.ErrTimeout) {
+					t.Errorf("expect ErrTimeout, got %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("the drain is not bounded by the write timeout")
+			}
+		})
+	}
+}
+
+func TestLinuxWriteMultidropAddressTimeout(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	// Nobody reads the remote side, so the output gets full. The pty moves the data
+	// to the remote side in the background, so it is full once a write times out with nothing sent.
+	port.SetTimeouts(serial.Timeouts{WriteTotal: 20 * time.Millisecond})
+	for {
+		n, err := port.Write(make([]byte, 1<<16))
+		if err != nil {
+			t.Fatalf("write error: %s", err)
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	n, err := port.WriteMultidrop(0x42, []byte{1, 2, 3})
+	if n != 0 || !errors.Is(err, serial.ErrTimeout) {
+		t.Errorf("expect 0 and ErrTimeout, got %d and %v", n, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Mark and space parity are not supported on target OS.
+
+func (p *serialPort) parityMode() (ParityMode, error) {
+	return PARITY_NONE, ErrNotImplementedOnOS
+}
+
+func (p *serialPort) setParityDrained(_ ParityMode) error {
+	return ErrNotImplementedOnOS
+}
@@ -0,0 +1,36 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+func (p *serialPort) parityMode() (ParityMode, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return PARITY_NONE, ErrInvalidOrNilPort
+	}
+	params, err := getCommState(p.fd)
+	if err != nil {
+		return PARITY_NONE, err
+	}
+	return ParityMode(params.Parity), nil
+}
+
+// Changes the parity mode once the output buffers are flushed.
+func (p *serialPort) setParityDrained(mode ParityMode) error {
+	if err := p.Drain(); err != nil {
+		return err
+	}
+	params, err := getCommState(p.fd)
+	if err != nil {
+		return err
+	}
+	params.Parity = byte(mode)
+	params.flags[0] &^= 0x02 // fParity
+	if mode != PARITY_NONE {
+		params.flags[0] |= 0x02
+	}
+	return setCommStateDCB(p.fd, params)
+}
@@ -1,7 +1,7 @@
 // ------------------------------------------
 // Modified by (c) 2024 Serge Reinov.
-//   The main code has not been changed.
-//   Only compatibility with the new object level has been added.
+//   Added compatibility with the new object level, exclusive and UUCP locks,
+//   restoring the settings on Close and Read timeouts emulated in user space.
 //
 // Licensed under the Apache License, Version 2.0.
 // Below is the license of the original project.
@@ -34,15 +34,40 @@
 package serial
 
 import (
-	"errors"
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
 type serialPort struct {
 	*os.File
+
+	mu          sync.Mutex
+	policy      readPolicy // read behavior derived from OpenOptions
+	timeouts    Timeouts   // set by SetTimeouts
+	useTimeouts bool       // true after the first SetTimeouts call
+
+	readDeadline  time.Time // set by SetReadDeadline
+	writeDeadline time.Time // set by SetWriteDeadline
+	readGen       uint64    // incremented on each SetReadDeadline call
+	writeGen      uint64    // incremented on each SetWriteDeadline call
+
+	markErrors     bool            // ReportLineErrors is on
+	decoder        parmrkDecoder   // decodes PARMRK marks of the input
+	statusBuf      []LineError     // status of the bytes for Read, reused
+	lastCounts     lineErrorCounts // driver error counters after the last read
+	countersFailed bool            // the driver does not provide error counters
+
+	exclusive bool          // flock and TIOCEXCL are held
+	lock      *uucpLock     // UUCP lock file, if any
+	snapshot  *portSnapshot // settings before Open, to restore on Close
+
+	closeOnce sync.Once   // Close does the work once
+	closed    atomic.Bool // set by Close, before the file is closed
 }
 
 var _ = io.ReadWriteCloser((*serialPort)(nil))
@@ -62,23 +87,33 @@ const (
 	kCREAD      = 0x00000800
 	kCSTOPB     = 0x00000400
 	kIGNPAR     = 0x00000004
+	kPARMRK     = 0x00000008
+	kINPCK      = 0x00000010
 	kPARENB     = 0x00001000
 	kPARODD     = 0x00002000
 	kCCTS_OFLOW = 0x00010000
 	kCRTS_IFLOW = 0x00020000
 	kCRTSCTS    = kCCTS_OFLOW | kCRTS_IFLOW
+	kCDTR_IFLOW = 0x00040000
+	kCDSR_OFLOW = 0x00080000
+	kIXON       = 0x00000200
+	kIXOFF      = 0x00000400
 
 	kNCCS = 20
 
-	kVMIN  = tcflag_t(16)
-	kVTIME = tcflag_t(17)
+	kVSTART = tcflag_t(12)
+	kVSTOP  = tcflag_t(13)
+	kVMIN   = tcflag_t(16)
+	kVTIME  = tcflag_t(17)
 )
 
 const (
 
 	// sys/ttycom.h
-	kTIOCGETA = 1078490131
-	kTIOCSETA = 2152231956
+	kTIOCGETA  = 1078490131
+	kTIOCSETA  = 2152231956
+	kTIOCSETAW = 2152231957 // drain output, then set
+	kTIOCSETAF = 2152231958 // drain output, flush input, then set
 
 	// IOKit: serial/ioss.h
 	kIOSSIOSPEED = 0x80045402
@@ -99,22 +134,28 @@ type termios struct {
 // descriptor. This sets appropriate options for how the OS interacts with the
 // port.
 func setTermios(fd uintptr, src *termios) error {
+	return setTermiosWith(fd, kTIOCSETA, src)
+}
+
+// setTermiosWith is setTermios with the given request:
+// TIOCSETA, TIOCSETAW or TIOCSETAF.
+func setTermiosWith(fd uintptr, req uintptr, src *termios) error {
 	// Make the ioctl syscall that sets the termios struct.
 	r1, _, errno :=
 		syscall.Syscall(
 			syscall.SYS_IOCTL,
 			fd,
-			uintptr(kTIOCSETA),
+			req,
 			uintptr(unsafe.Pointer(src)))
 
 	// Did the syscall return an error?
 	if errno != 0 {
-		return os.NewSyscallError("SYS_IOCTL", errno)
+		return ioError(os.NewSyscallError("SYS_IOCTL", errno))
 	}
 
 	// Just in case, check the return value as well.
 	if r1 != 0 {
-		return errors.New("Unknown error from SYS_IOCTL.")
+		return ioctlResultError(r1)
 	}
 
 	return nil
@@ -133,21 +174,15 @@ func convertOptions(options OpenOptions) (*termios, error) {
 	// seems to imply that it shouldn't really exist.
 	result.c_cflag |= kCREAD
 
-	// Sanity check inter-character timeout and minimum read size options.
-	vtime := uint(round(float64(options.InterCharacterTimeout)/100.0) * 100)
-	vmin := options.MinimumReadSize
-
-	if vmin == 0 && vtime < 100 {
-		return nil, errors.New("Invalid values for InterCharacterTimeout and MinimumReadSize.")
-	}
-
-	if vtime > 25500 {
-		return nil, errors.New("Invalid value for InterCharacterTimeout.")
+	// Sanity check the options.
+	if err := options.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Set VMIN and VTIME. Make sure to convert to tenths of seconds for VTIME.
-	result.c_cc[kVTIME] = cc_t(vtime / 100)
-	result.c_cc[kVMIN] = cc_t(vmin)
+	// Both options are handled by Read in user space (see `timeouts_unix.go`),
+	// so the kernel is asked for plain VMIN=1, VTIME=0 reads.
+	result.c_cc[kVTIME] = 0
+	result.c_cc[kVMIN] = 1
 
 	if !IsStandardBaudRate(options.BaudRate) {
 		// Non-standard baud-rates cannot be set via the standard IOCTL.
@@ -170,8 +205,6 @@ func convertOptions(options OpenOptions) (*termios, error) {
 		result.c_cflag |= kCS7
 	case 8:
 		result.c_cflag |= kCS8
-	default:
-		return nil, errors.New("Invalid setting for DataBits.")
 	}
 
 	// Stop bits
@@ -180,8 +213,6 @@ func convertOptions(options OpenOptions) (*termios, error) {
 		// Nothing to do; CSTOPB is already cleared.
 	case 2:
 		result.c_cflag |= kCSTOPB
-	default:
-		return nil, errors.New("Invalid setting for StopBits.")
 	}
 
 	// Parity mode
@@ -200,17 +231,63 @@ func convertOptions(options OpenOptions) (*termios, error) {
 		// not setting INPCK). Leave out PARODD to use even mode.
 		result.c_cflag |= kPARENB
 	default:
-		return nil, errors.New("Invalid setting for ParityMode.")
+		return nil, &OptionError{Field: "ParityMode", Value: options.ParityMode, Reason: "not supported on OS X"}
+	}
+
+	// Report errors of received bytes, see `linestatus.go`.
+	if options.ReportLineErrors {
+		result.c_iflag |= kINPCK | kPARMRK
 	}
 
-	if options.RTSCTSFlowControl {
+	// Flow control
+	switch options.flowControl() {
+	case FLOW_CONTROL_NONE:
+	case FLOW_CONTROL_RTSCTS:
 		result.c_cflag |= kCRTSCTS
+	case FLOW_CONTROL_XONXOFF:
+		xon, xoff := options.xonXoffChars()
+		result.c_iflag |= kIXON | kIXOFF
+		result.c_cc[kVSTART] = cc_t(xon)
+		result.c_cc[kVSTOP] = cc_t(xoff)
+	case FLOW_CONTROL_DTRDSR:
+		result.c_cflag |= kCDTR_IFLOW | kCDSR_OFLOW
 	}
 
 	return &result, nil
 }
 
-func openInternal(options OpenOptions) (*serialPort, error) {
+// setSpeed sets a non-standard baud rate with the IOSSIOSPEED ioctl.
+func setSpeed(fd uintptr, baudRate uint) error {
+	r2, _, errno2 := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		fd,
+		uintptr(kIOSSIOSPEED),
+		uintptr(unsafe.Pointer(&baudRate)))
+
+	if errno2 != 0 {
+		return ioError(os.NewSyscallError("SYS_IOCTL", errno2))
+	}
+
+	if r2 != 0 {
+		return ioctlResultError(r2)
+	}
+
+	return nil
+}
+
+func openInternal(options OpenOptions) (_ *serialPort, err error) {
+	var lock *uucpLock
+	if options.UUCPLock {
+		if lock, err = acquireUUCPLock(UUCPLockDir, options.PortName); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				lock.release()
+			}
+		}()
+	}
+
 	// Open the serial port in non-blocking mode, since otherwise the OS will
 	// wait for the CARRIER line to be asserted.
 	file, err :=
@@ -220,25 +297,40 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 			0600)
 
 	if err != nil {
-		return nil, err
+		return nil, openError(options.PortName, err)
 	}
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
 
-	// We want to do blocking I/O, so clear the non-blocking flag set above.
-	r1, _, errno :=
-		syscall.Syscall(
-			syscall.SYS_FCNTL,
-			uintptr(file.Fd()),
-			uintptr(syscall.F_SETFL),
-			uintptr(0))
+	port := &serialPort{File: file, lock: lock}
 
-	if errno != 0 {
-		return nil, os.NewSyscallError("SYS_FCNTL", errno)
+	// Take the port before changing its settings.
+	if options.Exclusive {
+		if err := port.lockExclusive(options.PortName); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				port.unlockExclusive()
+			}
+		}()
 	}
 
-	if r1 != 0 {
-		return nil, errors.New("Unknown error from SYS_FCNTL.")
+	if !options.KeepSettingsOnClose {
+		port.takeSnapshot()
+		defer func() {
+			if err != nil {
+				port.restoreSnapshot()
+			}
+		}()
 	}
 
+	// The non-blocking flag set above is kept on purpose: the file is then
+	// served by the runtime poller, which makes timeouts and deadlines work.
+
 	// Set standard termios options.
 	terminalOptions, err := convertOptions(options)
 	if err != nil {
@@ -251,22 +343,15 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 	}
 
 	if !IsStandardBaudRate(options.BaudRate) {
-		// Set baud rate with the IOSSIOSPEED ioctl, to support non-standard speeds.
-		r2, _, errno2 := syscall.Syscall(
-			syscall.SYS_IOCTL,
-			uintptr(file.Fd()),
-			uintptr(kIOSSIOSPEED),
-			uintptr(unsafe.Pointer(&options.BaudRate)))
-
-		if errno2 != 0 {
-			return nil, os.NewSyscallError("SYS_IOCTL", errno2)
-		}
-
-		if r2 != 0 {
-			return nil, errors.New("Unknown error from SYS_IOCTL.")
+		if err := setSpeed(file.Fd(), options.BaudRate); err != nil {
+			return nil, err
 		}
 	}
 
 	// We're done.
-	return &serialPort{file}, nil
+	port.policy = policyFromOpenOptions(options)
+	port.markErrors = options.ReportLineErrors
+	port.initLineErrorCounts()
+
+	return port, nil
 }
@@ -1,7 +1,7 @@
 // ------------------------------------------
 // Modified by (c) 2024 Serge Reinov.
-//   The main code has not been changed.
-//   Only compatibility with the new object level has been added.
+//   Added compatibility with the new object level, exclusive and UUCP locks,
+//   restoring the settings on Close and Read timeouts emulated in user space.
 //
 // Licensed under the Apache License, Version 2.0.
 // ------------------------------------------
@@ -9,10 +9,12 @@
 package serial
 
 import (
-	"errors"
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"golang.org/x/sys/unix"
@@ -20,6 +22,33 @@ import (
 
 type serialPort struct {
 	*os.File
+
+	mu          sync.Mutex
+	policy      readPolicy // read behavior derived from OpenOptions
+	timeouts    Timeouts   // set by SetTimeouts
+	useTimeouts bool       // true after the first SetTimeouts call
+
+	readDeadline  time.Time // set by SetReadDeadline
+	writeDeadline time.Time // set by SetWriteDeadline
+	readGen       uint64    // incremented on each SetReadDeadline call
+	writeGen      uint64    // incremented on each SetWriteDeadline call
+
+	dsrFlow bool // Write is gated by DSR, see setupFlowControl
+
+	markErrors     bool            // ReportLineErrors is on
+	decoder        parmrkDecoder   // decodes PARMRK marks of the input
+	statusBuf      []LineError     // status of the bytes for Read, reused
+	lastCounts     lineErrorCounts // driver error counters after the last read
+	countersFailed bool            // the driver does not provide error counters
+
+	exclusive bool      // flock and TIOCEXCL are held
+	lock      *uucpLock // UUCP lock file, if any
+
+	lowLatency *lowLatencyBackup // settings to restore on Close
+	snapshot   *portSnapshot     // settings before Open, to restore on Close
+
+	closeOnce sync.Once   // Close does the work once
+	closed    atomic.Bool // set by Close, before the file is closed
 }
 
 var _ = io.ReadWriteCloser((*serialPort)(nil))
@@ -60,44 +89,47 @@ type termios2 struct {
 	c_ospeed speed_t     // output speed
 }
 
-// Constants for RS485 operation
+// Constants for RS485 operation, see linux/serial.h
 
 const (
 	sER_RS485_ENABLED        = (1 << 0)
 	sER_RS485_RTS_ON_SEND    = (1 << 1)
 	sER_RS485_RTS_AFTER_SEND = (1 << 2)
 	sER_RS485_RX_DURING_TX   = (1 << 4)
+	sER_RS485_TERMINATE_BUS  = (1 << 5)
+	sER_RS485_ADDRB          = (1 << 6)
+	sER_RS485_ADDR_RECV      = (1 << 7)
+	sER_RS485_ADDR_DEST      = (1 << 8)
+	sER_RS485_MODE_RS422     = (1 << 9)
 	tIOCSRS485               = 0x542F
+	tIOCGRS485               = 0x542E
 )
 
 type serial_rs485 struct {
 	flags                 uint32
 	delay_rts_before_send uint32
 	delay_rts_after_send  uint32
-	padding               [5]uint32
+	addr_recv             uint8
+	addr_dest             uint8
+	padding0              [2]uint8
+	padding1              [4]uint32
 }
 
 // Returns a pointer to an instantiates termios2 struct, based on the given
 // OpenOptions. Termios2 is a Linux extension which allows arbitrary baud rates
 // to be specified.
 func makeTermios2(options OpenOptions) (*termios2, error) {
-
-	// Sanity check inter-character timeout and minimum read size options.
-
-	vtime := uint(round(float64(options.InterCharacterTimeout)/100.0) * 100)
-	vmin := options.MinimumReadSize
-
-	if vmin == 0 && vtime < 100 {
-		return nil, errors.New("invalid values for InterCharacterTimeout and MinimumReadSize")
+	if err := options.Validate(); err != nil {
+		return nil, err
 	}
 
-	if vtime > 25500 {
-		return nil, errors.New("invalid value for InterCharacterTimeout")
-	}
+	// Inter-character timeout and minimum read size options are handled
+	// by Read in user space with millisecond precision,
+	// so the kernel is asked for plain VMIN=1, VTIME=0 reads.
 
 	ccOpts := [kNCCS]cc_t{}
-	ccOpts[syscall.VTIME] = cc_t(vtime / 100)
-	ccOpts[syscall.VMIN] = cc_t(vmin)
+	ccOpts[syscall.VTIME] = 0
+	ccOpts[syscall.VMIN] = 1
 
 	t2 := &termios2{
 		c_cflag:  syscall.CLOCAL | syscall.CREAD | kBOTHER,
@@ -110,23 +142,13 @@ func makeTermios2(options OpenOptions) (*termios2, error) {
 	case 1:
 	case 2:
 		t2.c_cflag |= syscall.CSTOPB
-
-	default:
-		return nil, errors.New("invalid setting for StopBits")
 	}
 
-	switch options.ParityMode {
-	case PARITY_NONE:
-	case PARITY_ODD:
-		t2.c_cflag |= syscall.PARENB
-		t2.c_cflag |= syscall.PARODD
-
-	case PARITY_EVEN:
-		t2.c_cflag |= syscall.PARENB
-
-	default:
-		return nil, errors.New("invalid setting for ParityMode")
+	parity, err := parityFlags(options.ParityMode)
+	if err != nil {
+		return nil, err
 	}
+	t2.c_cflag |= parity
 
 	switch options.DataBits {
 	case 5:
@@ -137,18 +159,97 @@ func makeTermios2(options OpenOptions) (*termios2, error) {
 		t2.c_cflag |= syscall.CS7
 	case 8:
 		t2.c_cflag |= syscall.CS8
-	default:
-		return nil, errors.New("invalid setting for DataBits")
 	}
 
-	if options.RTSCTSFlowControl {
+	if options.ReportLineErrors {
+		t2.c_iflag |= syscall.INPCK | syscall.PARMRK
+	}
+
+	switch options.flowControl() {
+	case FLOW_CONTROL_NONE:
+	case FLOW_CONTROL_RTSCTS:
 		t2.c_cflag |= unix.CRTSCTS
+
+	case FLOW_CONTROL_XONXOFF:
+		xon, xoff := options.xonXoffChars()
+		t2.c_iflag |= syscall.IXON | syscall.IXOFF
+		t2.c_cc[syscall.VSTART] = cc_t(xon)
+		t2.c_cc[syscall.VSTOP] = cc_t(xoff)
+
+	case FLOW_CONTROL_DTRDSR:
+		// Emulated in user space, see `OpenOptions.FlowControl`.
+	}
+
+	return t2, nil
+}
+
+// Returns c_cflag bits for the given parity mode.
+func parityFlags(mode ParityMode) (tcflag_t, error) {
+	switch mode {
+	case PARITY_NONE:
+		return 0, nil
+	case PARITY_ODD:
+		return syscall.PARENB | syscall.PARODD, nil
+	case PARITY_EVEN:
+		return syscall.PARENB, nil
+	case PARITY_MARK:
+		return syscall.PARENB | syscall.PARODD | unix.CMSPAR, nil
+	case PARITY_SPACE:
+		return syscall.PARENB | unix.CMSPAR, nil
+	default:
+		return 0, &OptionError{Field: "ParityMode", Value: mode}
 	}
+}
 
+// Returns the parity mode encoded in c_cflag bits.
+func parityFromFlags(cflag tcflag_t) ParityMode {
+	switch {
+	case cflag&syscall.PARENB == 0:
+		return PARITY_NONE
+	case cflag&unix.CMSPAR != 0 && cflag&syscall.PARODD != 0:
+		return PARITY_MARK
+	case cflag&unix.CMSPAR != 0:
+		return PARITY_SPACE
+	case cflag&syscall.PARODD != 0:
+		return PARITY_ODD
+	default:
+		return PARITY_EVEN
+	}
+}
+
+// Reads the current termios2 struct of the port.
+func (p *serialPort) getTermios2() (*termios2, error) {
+	t2 := &termios2{}
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TCGETS2, uintptr(unsafe.Pointer(t2)))
+	})
+	if err != nil {
+		return nil, err
+	}
 	return t2, nil
 }
 
-func openInternal(options OpenOptions) (*serialPort, error) {
+// Applies the termios2 struct to the port.
+// The req is one of TCSETS2 (now), TCSETSW2 (after drain) or TCSETSF2 (after drain and input flush).
+func (p *serialPort) setTermios2(req uintptr, t2 *termios2) error {
+	return p.control(func(fd uintptr) error {
+		return ioctl(fd, req, uintptr(unsafe.Pointer(t2)))
+	})
+}
+
+func openInternal(options OpenOptions) (_ *serialPort, err error) {
+
+	var lock *uucpLock
+	if options.UUCPLock {
+		if lock, err = acquireUUCPLock(UUCPLockDir, options.PortName); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				lock.release()
+			}
+		}()
+	}
 
 	file, openErr :=
 		os.OpenFile(
@@ -156,13 +257,39 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 			syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_NONBLOCK,
 			0600)
 	if openErr != nil {
-		return nil, openErr
+		return nil, openError(options.PortName, openErr)
 	}
+	defer func() {
+		if err != nil {
+			file.Close()
+		}
+	}()
+
+	// The non-blocking flag set above is kept on purpose: the file is then
+	// served by the runtime poller, which lets Read and Write wait with
+	// millisecond precision (see `timeouts_unix.go`).
 
-	// Clear the non-blocking flag set above.
-	nonblockErr := syscall.SetNonblock(int(file.Fd()), false)
-	if nonblockErr != nil {
-		return nil, nonblockErr
+	port := &serialPort{File: file, lock: lock}
+
+	// Take the port before changing its settings.
+	if options.Exclusive {
+		if err := port.lockExclusive(options.PortName); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				port.unlockExclusive()
+			}
+		}()
+	}
+
+	if !options.KeepSettingsOnClose {
+		port.takeSnapshot()
+		defer func() {
+			if err != nil {
+				port.restoreSnapshot()
+			}
+		}()
 	}
 
 	t2, optErr := makeTermios2(options)
@@ -177,43 +304,30 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 		uintptr(unsafe.Pointer(t2)))
 
 	if errno != 0 {
-		return nil, os.NewSyscallError("SYS_IOCTL", errno)
+		return nil, ioError(os.NewSyscallError("SYS_IOCTL", errno))
 	}
 
 	if r != 0 {
-		return nil, errors.New("unknown error from SYS_IOCTL")
+		return nil, ioctlResultError(r)
 	}
 
 	if options.Rs485Enable {
-		rs485 := serial_rs485{
-			sER_RS485_ENABLED,
-			uint32(options.Rs485DelayRtsBeforeSend),
-			uint32(options.Rs485DelayRtsAfterSend),
-			[5]uint32{0, 0, 0, 0, 0},
-		}
-
-		if options.Rs485RtsHighDuringSend {
-			rs485.flags |= sER_RS485_RTS_ON_SEND
-		}
-
-		if options.Rs485RtsHighAfterSend {
-			rs485.flags |= sER_RS485_RTS_AFTER_SEND
+		if err := port.SetRS485(options.rs485Config()); err != nil {
+			return nil, err
 		}
+	}
 
-		r, _, errno := syscall.Syscall(
-			syscall.SYS_IOCTL,
-			uintptr(file.Fd()),
-			uintptr(tIOCSRS485),
-			uintptr(unsafe.Pointer(&rs485)))
+	port.policy = policyFromOpenOptions(options)
+	port.markErrors = options.ReportLineErrors
+	port.initLineErrorCounts()
 
-		if errno != 0 {
-			return nil, os.NewSyscallError("SYS_IOCTL (RS485)", errno)
-		}
+	if err := port.setupFlowControl(options); err != nil {
+		return nil, err
+	}
 
-		if r != 0 {
-			return nil, errors.New("Unknown error from SYS_IOCTL (RS485)")
-		}
+	if options.LowLatency {
+		port.enableLowLatency()
 	}
 
-	return &serialPort{file}, nil
+	return port, nil
 }
@@ -0,0 +1,163 @@
+package serial
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func newTestOptions() OpenOptions {
+	return OpenOptions{
+		PortName:              "/dev/null",
+		BaudRate:              9600,
+		DataBits:              8,
+		StopBits:              1,
+		InterCharacterTimeout: 100,
+	}
+}
+
+func TestMakeTermios2FlowControl(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(o *OpenOptions)
+		cflag  tcflag_t
+		iflag  tcflag_t
+		xon    byte
+		xoff   byte
+	}{
+		{"none", func(o *OpenOptions) {}, 0, 0, 0, 0},
+		{"rtscts alias", func(o *OpenOptions) { o.RTSCTSFlowControl = true }, unix.CRTSCTS, 0, 0, 0},
+		{"rtscts", func(o *OpenOptions) { o.FlowControl = FLOW_CONTROL_RTSCTS }, unix.CRTSCTS, 0, 0, 0},
+		{"xonxoff default chars", func(o *OpenOptions) { o.FlowControl = FLOW_CONTROL_XONXOFF },
+			0, syscall.IXON | syscall.IXOFF, DefaultXonChar, DefaultXoffChar},
+		{"xonxoff custom chars", func(o *OpenOptions) {
+			o.FlowControl = FLOW_CONTROL_XONXOFF
+			o.XonChar, o.XoffChar = 'Q', 'S'
+		}, 0, syscall.IXON | syscall.IXOFF, 'Q', 'S'},
+		{"dtrdsr is emulated", func(o *OpenOptions) { o.FlowControl = FLOW_CONTROL_DTRDSR }, 0, 0, 0, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := newTestOptions()
+			testCase.modify(&options)
+			t2, err := makeTermios2(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if t2.c_cflag&unix.CRTSCTS != testCase.cflag {
+				t.Errorf("expected CRTSCTS bit %#x, but got %#x", testCase.cflag, t2.c_cflag&unix.CRTSCTS)
+			}
+			if t2.c_iflag != testCase.iflag {
+				t.Errorf("expected c_iflag %#x, but got %#x", testCase.iflag, t2.c_iflag)
+			}
+			if byte(t2.c_cc[syscall.VSTART]) != testCase.xon || byte(t2.c_cc[syscall.VSTOP]) != testCase.xoff {
+				t.Errorf("expected XON/XOFF %#x/%#x, but got %#x/%#x",
+					testCase.xon, testCase.xoff, t2.c_cc[syscall.VSTART], t2.c_cc[syscall.VSTOP])
+			}
+		})
+	}
+
+	options := newTestOptions()
+	options.FlowControl = 42
+	if _, err := makeTermios2(options); err == nil {
+		t.Errorf("expected an error for invalid FlowControl")
+	}
+}
+
+func TestParityFlags(t *testing.T) {
+	for _, mode := range []ParityMode{PARITY_NONE, PARITY_ODD, PARITY_EVEN, PARITY_MARK, PARITY_SPACE} {
+		t.Run(fmt.Sprintf("%d", mode), func(t *testing.T) {
+			flags, err := parityFlags(mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result := parityFromFlags(flags | syscall.CS8); result != mode {
+				t.Errorf("expected parity %d, but got %d", mode, result)
+			}
+		})
+	}
+
+	if _, err := parityFlags(42); err == nil {
+		t.Errorf("expected an error for invalid ParityMode")
+	}
+}
+
+func TestModeFromTermios2(t *testing.T) {
+	options := newTestOptions()
+	options.BaudRate = 250000
+	options.DataBits = 7
+	options.StopBits = 2
+	options.ParityMode = PARITY_MARK
+	options.FlowControl = FLOW_CONTROL_XONXOFF
+	options.ReportLineErrors = true
+
+	t2, err := makeTermios2(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Mode{
+		InputBaudRate:    250000,
+		OutputBaudRate:   250000,
+		DataBits:         7,
+		StopBits:         2,
+		ParityMode:       PARITY_MARK,
+		FlowControl:      FLOW_CONTROL_XONXOFF,
+		XonChar:          DefaultXonChar,
+		XoffChar:         DefaultXoffChar,
+		ReportLineErrors: true,
+		Vmin:             1,
+	}
+	if mode := modeFromTermios2(t2); mode != expected {
+		t.Errorf("expected %+v, but got %+v", expected, mode)
+	}
+}
+
+// The layout must match struct serial_struct of linux/serial.h.
+func TestSerialStructSize(t *testing.T) {
+	expected := uintptr(60)
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		expected = 72
+	}
+	if size := unsafe.Sizeof(serial_struct{}); size != expected {
+		t.Errorf("expected sizeof(serial_struct) %d, but got %d", expected, size)
+	}
+}
+
+func TestSerialRS485(t *testing.T) {
+	config := RS485Config{
+		Enabled:                   true,
+		RtsHighDuringSend:         true,
+		RxDuringTx:                true,
+		TerminateBus:              true,
+		DelayRtsBeforeSend:        2,
+		DelayRtsAfterSend:         3,
+		AddressMode:               true,
+		ReceiveAddressEnabled:     true,
+		ReceiveAddress:            0x12,
+		DestinationAddressEnabled: true,
+		DestinationAddress:        0x34,
+	}
+
+	rs485 := makeSerialRS485(config)
+	flags := uint32(sER_RS485_ENABLED | sER_RS485_RTS_ON_SEND | sER_RS485_RX_DURING_TX |
+		sER_RS485_TERMINATE_BUS | sER_RS485_ADDRB | sER_RS485_ADDR_RECV | sER_RS485_ADDR_DEST)
+	if rs485.flags != flags {
+		t.Errorf("expected flags %#x, but got %#x", flags, rs485.flags)
+	}
+	if result := rs485Config(&rs485); result != config {
+		t.Errorf("expected %+v, but got %+v", config, result)
+	}
+
+	if size := unsafe.Sizeof(serial_rs485{}); size != 32 {
+		t.Errorf("expected sizeof(serial_rs485) 32, but got %d", size)
+	}
+
+	config.DelayRtsAfterSend = -1
+	if err := config.validate(); err == nil {
+		t.Errorf("expected an error for negative delay")
+	}
+}
@@ -30,12 +30,19 @@ package serial
 
 import (
 	"io"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"unsafe"
 )
 
 type serialPort struct {
-	fd syscall.Handle
+	fd          syscall.Handle
+	useTimeouts bool       // true after the first SetTimeouts call
+	snapshot    *structDCB // settings before Open, to restore on Close
+
+	closeOnce sync.Once   // Close does the work once
+	closed    atomic.Bool // set by Close, before the handle is closed
 }
 
 var _ = io.ReadWriteCloser((*serialPort)(nil))
@@ -50,6 +57,10 @@ type structDCB struct {
 }
 
 func openInternal(options OpenOptions) (*serialPort, error) {
+	if options.UUCPLock {
+		return nil, ErrNotImplementedOnOS
+	}
+
 	if len(options.PortName) > 0 && options.PortName[0] != '\\' {
 		options.PortName = "\\\\.\\" + options.PortName
 	}
@@ -67,10 +78,15 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 		syscall.FILE_ATTRIBUTE_NORMAL,
 		0)
 	if err != nil {
-		return nil, err
+		return nil, openError(options.PortName, err)
+	}
+	port := &serialPort{fd: h}
+	if !options.KeepSettingsOnClose {
+		port.takeSnapshot()
 	}
 	defer func() {
 		if err != nil {
+			port.restoreSnapshot()
 			syscall.CloseHandle(h)
 		}
 	}()
@@ -86,42 +102,48 @@ func openInternal(options OpenOptions) (*serialPort, error) {
 		return nil, err
 	}
 
-	port := new(serialPort)
-	port.fd = h
-
 	return port, nil
 }
 
-func (p *serialPort) Close() error {
-	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
-		return ErrInvalidOrNilPort
-	}
-	return syscall.CloseHandle(p.fd)
-}
-
 func (p *serialPort) Write(buf []byte) (int, error) {
 	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
 		return 0, ErrInvalidOrNilPort
 	}
+	if p.closed.Load() {
+		return 0, ErrPortClosed
+	}
 	var n uint32
 	err := syscall.WriteFile(p.fd, buf, &n, nil)
-	return int(n), err
+	return int(n), p.wrapError(err)
 }
 
 func (p *serialPort) Read(buf []byte) (int, error) {
 	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
 		return 0, ErrInvalidOrNilPort
 	}
+	if p.closed.Load() {
+		return 0, ErrPortClosed
+	}
 	var done uint32
 	err := syscall.ReadFile(p.fd, buf, &done, nil)
-	return int(done), err
+	return int(done), p.wrapError(err)
 }
 
 var (
+	nGetCommState,
 	nSetCommState,
 	nSetCommTimeouts,
 	nSetupComm,
-	nPurgeComm uintptr
+	nPurgeComm,
+	nGetCurrentThreadId,
+	nOpenThread,
+	nCancelSynchronousIo,
+	nCancelIoEx,
+	nEscapeCommFunction,
+	nGetCommModemStatus,
+	nSetCommBreak,
+	nClearCommBreak,
+	nClearCommError uintptr
 )
 
 func init() {
@@ -131,10 +153,20 @@ func init() {
 	}
 	defer syscall.FreeLibrary(k32)
 
+	nGetCommState = getProcAddr(k32, "GetCommState")
 	nSetCommState = getProcAddr(k32, "SetCommState")
 	nSetCommTimeouts = getProcAddr(k32, "SetCommTimeouts")
 	nSetupComm = getProcAddr(k32, "SetupComm")
 	nPurgeComm = getProcAddr(k32, "PurgeComm")
+	nGetCurrentThreadId = getProcAddr(k32, "GetCurrentThreadId")
+	nOpenThread = getProcAddr(k32, "OpenThread")
+	nCancelSynchronousIo = getProcAddr(k32, "CancelSynchronousIo")
+	nCancelIoEx = getProcAddr(k32, "CancelIoEx")
+	nEscapeCommFunction = getProcAddr(k32, "EscapeCommFunction")
+	nGetCommModemStatus = getProcAddr(k32, "GetCommModemStatus")
+	nSetCommBreak = getProcAddr(k32, "SetCommBreak")
+	nClearCommBreak = getProcAddr(k32, "ClearCommBreak")
+	nClearCommError = getProcAddr(k32, "ClearCommError")
 }
 
 func getProcAddr(lib syscall.Handle, name string) uintptr {
@@ -146,6 +178,10 @@ func getProcAddr(lib syscall.Handle, name string) uintptr {
 }
 
 func setCommState(h syscall.Handle, options OpenOptions) error {
+	if options.ReportLineErrors {
+		return ErrNotImplementedOnOS
+	}
+
 	var params structDCB
 	params.DCBlength = uint32(unsafe.Sizeof(params))
 
@@ -166,22 +202,47 @@ func setCommState(h syscall.Handle, options OpenOptions) error {
 	params.BaudRate = uint32(options.BaudRate)
 	params.ByteSize = byte(options.DataBits)
 
-	if options.RTSCTSFlowControl {
+	switch options.flowControl() {
+	case FLOW_CONTROL_RTSCTS:
 		params.flags[0] |= 0x04 // fOutxCtsFlow = 0x1
 		params.flags[1] |= 0x20 // fRtsControl = RTS_CONTROL_HANDSHAKE (0x2)
+	case FLOW_CONTROL_XONXOFF:
+		params.flags[1] |= 0x01 // fOutX
+		params.flags[1] |= 0x02 // fInX
+		params.XonChar, params.XoffChar = options.xonXoffChars()
+		params.XonLim = 16
+		params.XoffLim = 16
+	case FLOW_CONTROL_DTRDSR:
+		params.flags[0] |= 0x08  // fOutxDsrFlow = 0x1
+		params.flags[0] &^= 0x10 // fDtrControl = DTR_CONTROL_HANDSHAKE (0x2)
+		params.flags[0] |= 0x20
 	}
 
-	r, _, err := syscall.SyscallN(nSetCommState, uintptr(h), uintptr(unsafe.Pointer(&params)), 0)
+	return setCommStateDCB(h, &params)
+}
+
+func setCommStateDCB(h syscall.Handle, params *structDCB) error {
+	r, _, err := syscall.SyscallN(nSetCommState, uintptr(h), uintptr(unsafe.Pointer(params)), 0)
 	if r == 0 {
-		return err
+		return ioError(err)
 	}
 	return nil
 }
 
+func getCommState(h syscall.Handle) (*structDCB, error) {
+	var params structDCB
+	params.DCBlength = uint32(unsafe.Sizeof(params))
+	r, _, err := syscall.SyscallN(nGetCommState, uintptr(h), uintptr(unsafe.Pointer(&params)), 0)
+	if r == 0 {
+		return nil, ioError(err)
+	}
+	return &params, nil
+}
+
 func setCommTimeouts(h syscall.Handle, cto WindowsCommTimeouts) error {
 	r, _, err := syscall.SyscallN(nSetCommTimeouts, uintptr(h), uintptr(unsafe.Pointer(&cto)), 0)
 	if r == 0 {
-		return err
+		return ioError(err)
 	}
 	return nil
 }
@@ -189,7 +250,7 @@ func setCommTimeouts(h syscall.Handle, cto WindowsCommTimeouts) error {
 func setupComm(h syscall.Handle, in, out int) error {
 	r, _, err := syscall.SyscallN(nSetupComm, uintptr(h), uintptr(in), uintptr(out))
 	if r == 0 {
-		return err
+		return ioError(err)
 	}
 	return nil
 }
@@ -208,7 +269,87 @@ func purgeComm(h syscall.Handle, clearRx, clearTx bool) error {
 	}
 	rBool, _, err := syscall.SyscallN(nPurgeComm, uintptr(h), uintptr(flags))
 	if rBool == 0 {
+		return ioError(err)
+	}
+	return nil
+}
+
+// Opens a real handle of the current OS thread, suitable for cancelSynchronousIo.
+// The caller must lock the goroutine to the thread and close the handle.
+func openCurrentThread() (syscall.Handle, error) {
+	const THREAD_TERMINATE = 0x0001
+	id, _, _ := syscall.SyscallN(nGetCurrentThreadId)
+	h, _, err := syscall.SyscallN(nOpenThread, THREAD_TERMINATE, 0, id)
+	if h == 0 {
+		return syscall.InvalidHandle, err
+	}
+	return syscall.Handle(h), nil
+}
+
+func cancelSynchronousIo(thread syscall.Handle) error {
+	r, _, err := syscall.SyscallN(nCancelSynchronousIo, uintptr(thread))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// Cancels the IO of all threads of the process on the handle, including synchronous IO.
+func cancelIoEx(h syscall.Handle) error {
+	r, _, err := syscall.SyscallN(nCancelIoEx, uintptr(h), 0)
+	if r == 0 {
 		return err
 	}
 	return nil
 }
+
+func escapeCommFunction(h syscall.Handle, fn uint32) error {
+	r, _, err := syscall.SyscallN(nEscapeCommFunction, uintptr(h), uintptr(fn))
+	if r == 0 {
+		return ioError(err)
+	}
+	return nil
+}
+
+func getCommModemStatus(h syscall.Handle) (uint32, error) {
+	var status uint32
+	r, _, err := syscall.SyscallN(nGetCommModemStatus, uintptr(h), uintptr(unsafe.Pointer(&status)))
+	if r == 0 {
+		return 0, ioError(err)
+	}
+	return status, nil
+}
+
+func setCommBreak(h syscall.Handle) error {
+	r, _, err := syscall.SyscallN(nSetCommBreak, uintptr(h))
+	if r == 0 {
+		return ioError(err)
+	}
+	return nil
+}
+
+func clearCommBreak(h syscall.Handle) error {
+	r, _, err := syscall.SyscallN(nClearCommBreak, uintptr(h))
+	if r == 0 {
+		return ioError(err)
+	}
+	return nil
+}
+
+type structCOMSTAT struct {
+	flags    uint32
+	cbInQue  uint32
+	cbOutQue uint32
+}
+
+// Returns and clears the communication errors, and returns the queue status.
+func clearCommError(h syscall.Handle) (uint32, *structCOMSTAT, error) {
+	var errors uint32
+	var stat structCOMSTAT
+	r, _, err := syscall.SyscallN(nClearCommError, uintptr(h),
+		uintptr(unsafe.Pointer(&errors)), uintptr(unsafe.Pointer(&stat)))
+	if r == 0 {
+		return 0, nil, ioError(err)
+	}
+	return errors, &stat, nil
+}
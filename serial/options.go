@@ -11,9 +11,27 @@ package serial
 type ParityMode int
 
 const (
-	PARITY_NONE ParityMode = 0
-	PARITY_ODD  ParityMode = 1
-	PARITY_EVEN ParityMode = 2
+	PARITY_NONE  ParityMode = 0
+	PARITY_ODD   ParityMode = 1
+	PARITY_EVEN  ParityMode = 2
+	PARITY_MARK  ParityMode = 3 // The parity bit is always 1. Not supported on OS X.
+	PARITY_SPACE ParityMode = 4 // The parity bit is always 0. Not supported on OS X.
+)
+
+// Valid flow control values.
+type FlowControl int
+
+const (
+	FLOW_CONTROL_NONE    FlowControl = 0
+	FLOW_CONTROL_RTSCTS  FlowControl = 1 // Hardware flow control with RTS/CTS lines.
+	FLOW_CONTROL_XONXOFF FlowControl = 2 // Software flow control with XON/XOFF characters.
+	FLOW_CONTROL_DTRDSR  FlowControl = 3 // Hardware flow control with DTR/DSR lines.
+)
+
+// Standard XON/XOFF characters (DC1/DC3).
+const (
+	DefaultXonChar  byte = 0x11
+	DefaultXoffChar byte = 0x13
 )
 
 var (
@@ -54,6 +72,10 @@ func IsStandardBaudRate(baudRate uint) bool { return StandardBaudRates[baudRate]
 // opening a serial port.
 type OpenOptions struct {
 	// The name of the port, e.g. "/dev/tty.usbserial-A8008HlV".
+	//
+	// On Linux it may also be a selector resolved with ListPorts at open time,
+	// e.g. "usb:0403:6001:A1B2C3" or "by-id:usb-FTDI_FT232R_USB_UART_A1B2C3-if00-port0".
+	// See SELECTOR_USB, SELECTOR_BY_ID and SELECTOR_BY_PATH.
 	PortName string
 
 	// The baud rate for the port.
@@ -65,14 +87,66 @@ type OpenOptions struct {
 	// The number of stop bits per frame. Legal values are 1 and 2.
 	StopBits uint
 
-	// The type of parity bits to use for the connection. Currently parity errors
+	// The type of parity bits to use for the connection. By default parity errors
 	// are simply ignored; that is, bytes are delivered to the user no matter
 	// whether they were received with a parity error or not.
+	// See ReportLineErrors to change this.
 	ParityMode ParityMode
 
+	// Report parity, framing, overrun and break errors of received bytes.
+	// Read then returns a *LineStatusError along with the data that has errors,
+	// and ReadWithStatus reports the errors of every byte.
+	// Overruns have no position in the data, LineStatusError only counts them.
+	// Supported on Linux and OS X (termios INPCK and PARMRK).
+	ReportLineErrors bool
+
+	// Open the port for exclusive use. Open fails with *PortBusyError
+	// if another process has opened the port exclusively.
+	//
+	// On Linux and OS X it is done with flock and TIOCEXCL, which are released on Close.
+	// Note that root can still open a port in TIOCEXCL mode.
+	// Windows always opens ports exclusively.
+	Exclusive bool
+
+	// Create a UUCP-style lock file, e.g. /var/lock/LCK..ttyUSB0, holding the PID
+	// of this process, the same way as minicom and pppd do. Open fails with
+	// *PortBusyError if a running process holds the lock, stale lock files are removed.
+	// The directory is set by UUCPLockDir. Linux and OS X only.
+	UUCPLock bool
+
+	// Reduce the receive latency of the port: set ASYNC_LOW_LATENCY with TIOCSSERIAL
+	// and, for USB adapters like ftdi_sio, set the sysfs latency_timer to 1 ms
+	// (the default is 16 ms). The previous settings are restored on Close.
+	//
+	// It is done on a best effort basis, since not every driver supports it and
+	// writing latency_timer may need root. Use Port.LowLatency to check the result.
+	// Linux only.
+	LowLatency bool
+
+	// Leave the port as it is on Close. By default Close restores the settings
+	// the port had before Open: termios, RS485 settings and the DTR/RTS lines on Linux and OS X,
+	// and the DCB on Windows. On Linux and OS X Close waits up to a second for the pending
+	// output to be transmitted with the current settings before it restores the old ones.
+	KeepSettingsOnClose bool
+
 	// Enable RTS/CTS (hardware) flow control.
+	// It is an alias for `FlowControl: FLOW_CONTROL_RTSCTS`
+	// and is used only when FlowControl is FLOW_CONTROL_NONE.
 	RTSCTSFlowControl bool
 
+	// The type of flow control to use for the connection.
+	//
+	// FLOW_CONTROL_DTRDSR has no termios flag on Linux, so only its output side is emulated
+	// in user space: Write passes data to the driver only while DSR is asserted, keeping
+	// at most 64 bytes in the output queue. There is no input flow control, DTR is kept
+	// asserted while the port is open. On macOS the driver handles both sides.
+	FlowControl FlowControl
+
+	// Characters for FLOW_CONTROL_XONXOFF. Zero means the standard DC1/DC3
+	// (see DefaultXonChar and DefaultXoffChar).
+	XonChar  byte
+	XoffChar byte
+
 	// An inter-character timeout value, in milliseconds, and a minimum number of
 	// bytes to block for on each read. A call to Read() that otherwise may block
 	// waiting for more data will return immediately if the specified amount of
@@ -121,6 +195,11 @@ type OpenOptions struct {
 	//			the port to either wait until IntercharacterTimeout wait time is
 	//			exceeded OR there is character data to return from the port.
 	//
+	// On Linux and macOS these options are emulated in user space with millisecond
+	//     precision, so there is no rounding to 100 ms and InterCharacterTimeout
+	//     may be any positive value. `SetTimeouts` overrides these options
+	//     for all subsequent Read() calls.
+	//
 	// Comment from SR:
 	//     use `PlatformSpecificOptions` for more precise timeouts on windows.
 	//     (see `specific_windows.go` file for details)
@@ -147,3 +226,52 @@ type OpenOptions struct {
 	// RTS delay after send
 	Rs485DelayRtsAfterSend int
 }
+
+// Validate checks the options the way Open does on Linux and OS X, but without
+// opening the port. It returns *OptionError for the first invalid setting.
+// Settings that the OS or the driver does not support are only found by Open.
+func (o OpenOptions) Validate() error {
+	if o.BaudRate == 0 {
+		// B0 is not a speed, termios uses it to hang up the line.
+		return &OptionError{Field: "BaudRate", Value: o.BaudRate}
+	}
+	if o.MinimumReadSize == 0 && o.InterCharacterTimeout == 0 {
+		return &OptionError{Field: "InterCharacterTimeout", Value: 0, Reason: "MinimumReadSize is 0 too"}
+	}
+	if o.DataBits < 5 || o.DataBits > 8 {
+		return &OptionError{Field: "DataBits", Value: o.DataBits}
+	}
+	if o.StopBits != 1 && o.StopBits != 2 {
+		return &OptionError{Field: "StopBits", Value: o.StopBits}
+	}
+	if o.ParityMode < PARITY_NONE || o.ParityMode > PARITY_SPACE {
+		return &OptionError{Field: "ParityMode", Value: o.ParityMode}
+	}
+	if fc := o.flowControl(); fc < FLOW_CONTROL_NONE || fc > FLOW_CONTROL_DTRDSR {
+		return &OptionError{Field: "FlowControl", Value: o.FlowControl}
+	}
+	if o.Rs485Enable {
+		return o.rs485Config().validate()
+	}
+	return nil
+}
+
+// Returns the flow control mode, taking the `RTSCTSFlowControl` alias into account.
+func (o OpenOptions) flowControl() FlowControl {
+	if o.FlowControl == FLOW_CONTROL_NONE && o.RTSCTSFlowControl {
+		return FLOW_CONTROL_RTSCTS
+	}
+	return o.FlowControl
+}
+
+// Returns XON/XOFF characters with the defaults applied.
+func (o OpenOptions) xonXoffChars() (xon, xoff byte) {
+	xon, xoff = o.XonChar, o.XoffChar
+	if xon == 0 {
+		xon = DefaultXonChar
+	}
+	if xoff == 0 {
+		xoff = DefaultXoffChar
+	}
+	return xon, xoff
+}
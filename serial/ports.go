@@ -0,0 +1,42 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "fmt"
+
+// PortInfo describes a serial port found by ListPorts.
+type PortInfo struct {
+	// Device path to be used as OpenOptions.PortName, e.g. "/dev/ttyUSB0".
+	Name string
+
+	// Name of the kernel driver, e.g. "ftdi_sio", "cdc_acm" or "serial8250".
+	Driver string
+
+	// USB metadata. Filled only if IsUSB is true.
+	IsUSB        bool
+	VID          uint16
+	PID          uint16
+	SerialNumber string
+	Manufacturer string
+	Product      string
+
+	// Stable udev aliases from /dev/serial/by-id and /dev/serial/by-path.
+	ByID   []string
+	ByPath []string
+}
+
+// Returns a short description of the port for logs and error messages,
+// e.g. "/dev/ttyUSB0 (ftdi_sio, usb 0403:6001, serial A1B2C3)".
+func (p PortInfo) String() string {
+	s := p.Name + " (" + p.Driver
+	if p.IsUSB {
+		s += fmt.Sprintf(", usb %04x:%04x", p.VID, p.PID)
+		if p.SerialNumber != "" {
+			s += ", serial " + p.SerialNumber
+		}
+	}
+	return s + ")"
+}
@@ -0,0 +1,155 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysfs points to the sysfs and devfs trees used to enumerate ports.
+// Tests replace it with a fake tree.
+type sysfs struct {
+	sys string // normally "/sys"
+	dev string // normally "/dev"
+}
+
+var defaultSysfs = sysfs{sys: "/sys", dev: "/dev"}
+
+// ListPorts returns the serial ports found in /sys/class/tty, sorted by name.
+// Virtual terminals and legacy 8250 ports without hardware behind them are skipped.
+func ListPorts() ([]PortInfo, error) {
+	return defaultSysfs.listPorts()
+}
+
+func (fs sysfs) listPorts() ([]PortInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(fs.sys, "class", "tty"))
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := fs.aliases()
+
+	var ports []PortInfo
+	for _, entry := range entries {
+		info, ok := fs.portInfo(entry.Name())
+		if !ok {
+			continue
+		}
+		info.ByID = aliases[info.Name].byID
+		info.ByPath = aliases[info.Name].byPath
+		ports = append(ports, info)
+	}
+
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Name < ports[j].Name })
+	return ports, nil
+}
+
+// Returns the description of the tty without aliases,
+// or false if the tty is not a serial port.
+func (fs sysfs) portInfo(tty string) (PortInfo, bool) {
+	ttyDir := filepath.Join(fs.sys, "class", "tty", tty)
+
+	// Virtual terminals, ptys and the like have no device.
+	device, err := filepath.EvalSymlinks(filepath.Join(ttyDir, "device"))
+	if err != nil {
+		return PortInfo{}, false
+	}
+
+	// Serial core reports PORT_UNKNOWN for ports that are not present.
+	if readAttr(ttyDir, "type") == "0" {
+		return PortInfo{}, false
+	}
+
+	info := PortInfo{Name: filepath.Join(fs.dev, tty)}
+
+	// Since Linux 6.5 serial core ports sit on the "serial-base" bus
+	// below the device that is bound to the real driver.
+	driverDir := device
+	for linkBase(filepath.Join(driverDir, "subsystem")) == "serial-base" {
+		driverDir = filepath.Dir(driverDir)
+	}
+	info.Driver = linkBase(filepath.Join(driverDir, "driver"))
+
+	// The USB device is one of the parents of the tty device.
+	sys := filepath.Clean(fs.sys)
+	for dir := device; strings.HasPrefix(dir, sys+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		vid, err := strconv.ParseUint(readAttr(dir, "idVendor"), 16, 16)
+		if err != nil {
+			continue
+		}
+		pid, _ := strconv.ParseUint(readAttr(dir, "idProduct"), 16, 16)
+
+		info.IsUSB = true
+		info.VID = uint16(vid)
+		info.PID = uint16(pid)
+		info.SerialNumber = readAttr(dir, "serial")
+		info.Manufacturer = readAttr(dir, "manufacturer")
+		info.Product = readAttr(dir, "product")
+		break
+	}
+
+	return info, true
+}
+
+type portAliases struct {
+	byID   []string
+	byPath []string
+}
+
+// Returns udev aliases of /dev/serial/by-id and /dev/serial/by-path
+// mapped by the device path they point to.
+func (fs sysfs) aliases() map[string]portAliases {
+	result := make(map[string]portAliases)
+	for _, kind := range []string{"by-id", "by-path"} {
+		dir := filepath.Join(fs.dev, "serial", kind)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			link := filepath.Join(dir, entry.Name())
+			target, err := os.Readlink(link)
+			if err != nil {
+				continue
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			target = filepath.Clean(target)
+
+			a := result[target]
+			if kind == "by-id" {
+				a.byID = append(a.byID, link)
+			} else {
+				a.byPath = append(a.byPath, link)
+			}
+			result[target] = a
+		}
+	}
+	return result
+}
+
+// Returns the last element of the symlink target, or an empty string on errors.
+func linkBase(name string) string {
+	target, err := os.Readlink(name)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(target)
+}
+
+// Reads a sysfs attribute, returns an empty string on errors.
+func readAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,160 @@
+package serial
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// A fake sysfs and devfs tree in a temporary directory,
+// laid out the same way as the kernel and udev do it.
+type fakeSysfs struct {
+	t *testing.T
+	sysfs
+}
+
+func newFakeSysfs(t *testing.T) *fakeSysfs {
+	root := t.TempDir()
+	f := &fakeSysfs{t: t, sysfs: sysfs{sys: filepath.Join(root, "sys"), dev: filepath.Join(root, "dev")}}
+	f.mkdir(filepath.Join(f.sys, "class", "tty"))
+	f.mkdir(filepath.Join(f.dev, "serial", "by-id"))
+	f.mkdir(filepath.Join(f.dev, "serial", "by-path"))
+	return f
+}
+
+// Adds a tty. The usb attributes (idVendor, idProduct, serial, ...) make it a USB device,
+// a nil map makes it a platform device, and an empty driver makes it a virtual terminal.
+func (f *fakeSysfs) addPort(tty, driver string, usb map[string]string) {
+	f.t.Helper()
+
+	var device string
+	switch {
+	case driver == "":
+		device = filepath.Join(f.sys, "devices", "virtual", "tty", tty)
+	case usb != nil:
+		usbDir := filepath.Join(f.sys, "devices", "pci0000:00", "usb1", "1-"+tty)
+		for name, value := range usb {
+			f.write(filepath.Join(usbDir, name), value)
+		}
+		device = filepath.Join(usbDir, "1-"+tty+":1.0", tty)
+	default:
+		// A serial core port on the "serial-base" bus of Linux 6.5+.
+		parent := filepath.Join(f.sys, "devices", "platform", "serial8250")
+		f.linkDriver(parent, driver)
+		ctrl := filepath.Join(parent, "serial8250:"+tty)
+		device = filepath.Join(ctrl, "serial8250:"+tty+".0")
+		f.mkdir(filepath.Join(f.sys, "bus", "serial-base"))
+		f.symlink(filepath.Join(f.sys, "bus", "serial-base"), filepath.Join(ctrl, "subsystem"))
+		f.symlink(filepath.Join(f.sys, "bus", "serial-base"), filepath.Join(device, "subsystem"))
+		driver = "port"
+	}
+
+	ttyDir := filepath.Join(device, "tty", tty)
+	if driver == "" {
+		ttyDir = device
+	}
+	f.mkdir(ttyDir)
+	f.symlink(ttyDir, filepath.Join(f.sys, "class", "tty", tty))
+
+	if driver != "" {
+		f.linkDriver(device, driver)
+		f.symlink(device, filepath.Join(ttyDir, "device"))
+	}
+}
+
+func (f *fakeSysfs) linkDriver(device, driver string) {
+	f.t.Helper()
+	name := filepath.Join(device, "driver")
+	if _, err := os.Lstat(name); err == nil {
+		return
+	}
+	driverDir := filepath.Join(f.sys, "bus", "drivers", driver)
+	f.mkdir(driverDir)
+	f.symlink(driverDir, name)
+}
+
+func (f *fakeSysfs) removePort(tty string) {
+	f.t.Helper()
+	if err := os.Remove(filepath.Join(f.sys, "class", "tty", tty)); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// Adds a relative udev symlink of the given kind ("by-id" or "by-path").
+func (f *fakeSysfs) addAlias(kind, name, tty string) {
+	f.t.Helper()
+	f.symlink(filepath.Join("..", "..", tty), filepath.Join(f.dev, "serial", kind, name))
+}
+
+func (f *fakeSysfs) mkdir(dir string) {
+	f.t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func (f *fakeSysfs) write(name, value string) {
+	f.t.Helper()
+	f.mkdir(filepath.Dir(name))
+	if err := os.WriteFile(name, []byte(value+"\n"), 0644); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func (f *fakeSysfs) symlink(target, name string) {
+	f.t.Helper()
+	f.mkdir(filepath.Dir(name))
+	if err := os.Symlink(target, name); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+func ftdiAttrs(serial string) map[string]string {
+	return map[string]string{
+		"idVendor":     "0403",
+		"idProduct":    "6001",
+		"serial":       serial,
+		"manufacturer": "FTDI",
+		"product":      "FT232R USB UART",
+	}
+}
+
+func TestListPorts(t *testing.T) {
+	fs := newFakeSysfs(t)
+	fs.addPort("tty0", "", nil)
+	fs.addPort("ttyS0", "serial8250", nil)
+	fs.write(filepath.Join(fs.sys, "class", "tty", "ttyS0", "type"), "0")
+	fs.addPort("ttyS1", "serial8250", nil)
+	fs.write(filepath.Join(fs.sys, "class", "tty", "ttyS1", "type"), "4")
+	fs.addPort("ttyUSB0", "ftdi_sio", ftdiAttrs("A1B2C3"))
+	fs.addAlias("by-id", "usb-FTDI_FT232R_USB_UART_A1B2C3-if00-port0", "ttyUSB0")
+	fs.addAlias("by-path", "pci-0000:00:14.0-usb-0:2:1.0-port0", "ttyUSB0")
+
+	ports, err := fs.listPorts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []PortInfo{
+		{
+			Name:   filepath.Join(fs.dev, "ttyS1"),
+			Driver: "serial8250",
+		},
+		{
+			Name:         filepath.Join(fs.dev, "ttyUSB0"),
+			Driver:       "ftdi_sio",
+			IsUSB:        true,
+			VID:          0x0403,
+			PID:          0x6001,
+			SerialNumber: "A1B2C3",
+			Manufacturer: "FTDI",
+			Product:      "FT232R USB UART",
+			ByID:         []string{filepath.Join(fs.dev, "serial", "by-id", "usb-FTDI_FT232R_USB_UART_A1B2C3-if00-port0")},
+			ByPath:       []string{filepath.Join(fs.dev, "serial", "by-path", "pci-0000:00:14.0-usb-0:2:1.0-port0")},
+		},
+	}
+	if !reflect.DeepEqual(ports, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, ports)
+	}
+}
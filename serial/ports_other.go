@@ -0,0 +1,13 @@
+//go:build !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Port enumeration is not supported on target OS.
+func ListPorts() ([]PortInfo, error) {
+	return nil, ErrNotImplementedOnOS
+}
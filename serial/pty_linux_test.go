@@ -0,0 +1,41 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// Linux tests do not need any hardware.
+// A pseudo terminal pair plays the role of two ports connected by a null-modem cable:
+//   - the slave side `/dev/pts/N` is opened by `serial.Open` as usual,
+//   - the master side is used by the test as the remote device.
+
+// Opens a new pseudo terminal pair.
+// Returns the master side and the path to the slave side.
+func openPty(t *testing.T) (*os.File, string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo terminals are not available: %s", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("failed to unlock pty: %s", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("failed to get pty number: %s", err)
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
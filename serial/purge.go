@@ -0,0 +1,24 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// PurgeResult describes what was discarded by `PurgeBuffersWithResult`.
+type PurgeResult struct {
+	// Which queues were flushed.
+	RxPurged bool
+	TxPurged bool
+
+	// Number of bytes dropped from each queue, as reported by the driver
+	// right before the flush. -1 means that the OS does not expose it.
+	RxDropped int
+	TxDropped int
+}
+
+// PurgeBuffers discards the data in the input and/or output queues.
+func (p *serialPort) PurgeBuffers(clearRx, clearTx bool) error {
+	_, err := p.PurgeBuffersWithResult(clearRx, clearTx)
+	return err
+}
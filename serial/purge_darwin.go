@@ -0,0 +1,54 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// sys/fcntl.h and sys/filio.h
+const (
+	kFREAD    = 0x0001
+	kFWRITE   = 0x0002
+	kFIONREAD = 0x4004667f
+)
+
+// Purges input and output buffers with TIOCFLUSH (the same ioctl that tcflush uses).
+// The number of dropped bytes is taken from FIONREAD/TIOCOUTQ right before the flush.
+func (p *serialPort) PurgeBuffersWithResult(clearRx, clearTx bool) (PurgeResult, error) {
+	result := PurgeResult{RxDropped: -1, TxDropped: -1}
+
+	var which int32
+	if clearRx {
+		which |= kFREAD
+	}
+	if clearTx {
+		which |= kFWRITE
+	}
+	if which == 0 {
+		return result, nil
+	}
+
+	err := p.control(func(fd uintptr) error {
+		var n int32
+		if clearRx && ioctl(fd, kFIONREAD, uintptr(unsafe.Pointer(&n))) == nil {
+			result.RxDropped = int(n)
+		}
+		if clearTx && ioctl(fd, unix.TIOCOUTQ, uintptr(unsafe.Pointer(&n))) == nil {
+			result.TxDropped = int(n)
+		}
+		return ioctl(fd, unix.TIOCFLUSH, uintptr(unsafe.Pointer(&which)))
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.RxPurged = clearRx
+	result.TxPurged = clearTx
+	return result, nil
+}
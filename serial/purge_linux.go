@@ -0,0 +1,51 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// FIONREAD is also known as TIOCINQ on Linux.
+const kFIONREAD = unix.TIOCINQ
+
+// Purges input and output buffers with TCFLSH.
+// The number of dropped bytes is taken from TIOCINQ/TIOCOUTQ right before the flush.
+func (p *serialPort) PurgeBuffersWithResult(clearRx, clearTx bool) (PurgeResult, error) {
+	result := PurgeResult{RxDropped: -1, TxDropped: -1}
+
+	var queue uintptr
+	switch {
+	case clearRx && clearTx:
+		queue = unix.TCIOFLUSH
+	case clearRx:
+		queue = unix.TCIFLUSH
+	case clearTx:
+		queue = unix.TCOFLUSH
+	default:
+		return result, nil
+	}
+
+	err := p.control(func(fd uintptr) error {
+		var n int32
+		if clearRx && ioctl(fd, kFIONREAD, uintptr(unsafe.Pointer(&n))) == nil {
+			result.RxDropped = int(n)
+		}
+		if clearTx && ioctl(fd, unix.TIOCOUTQ, uintptr(unsafe.Pointer(&n))) == nil {
+			result.TxDropped = int(n)
+		}
+		return ioctl(fd, unix.TCFLSH, queue)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.RxPurged = clearRx
+	result.TxPurged = clearTx
+	return result, nil
+}
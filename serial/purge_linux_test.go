@@ -0,0 +1,38 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxPurgeBuffers(t *testing.T) {
+	port, remote := openPtyPort(t)
+
+	if _, err := remote.Write([]byte("stale reply")); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+	time.Sleep(time.Millisecond * 20) // let the pty deliver the data
+
+	result, err := port.PurgeBuffersWithResult(true, false)
+	if err != nil {
+		t.Fatalf("purge error: %s", err)
+	}
+	expect := serial.PurgeResult{RxPurged: true, RxDropped: len("stale reply"), TxDropped: -1}
+	if result != expect {
+		t.Errorf("expect %+v, got %+v", expect, result)
+	}
+
+	timeouts := serial.DefaultTimeouts()
+	timeouts.ReadTotal = time.Millisecond * 50
+	n, err := port.ReadWithTimeouts(make([]byte, 32), timeouts)
+	if err != nil || n != 0 {
+		t.Errorf("expect empty input queue, got %d bytes and %v", n, err)
+	}
+}
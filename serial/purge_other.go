@@ -1,4 +1,4 @@
-//go:build !windows
+//go:build !windows && !linux && !darwin
 
 // ------------------------------------------
 // Created by (c) 2024 Serge Reinov.
@@ -8,7 +8,7 @@
 package serial
 
 // Do nothing on target OS
-func (p *serialPort) PurgeBuffers(_, _ bool) error {
+func (p *serialPort) PurgeBuffersWithResult(_, _ bool) (PurgeResult, error) {
 	// skip until not implemented
-	return nil
+	return PurgeResult{RxDropped: -1, TxDropped: -1}, nil
 }
@@ -6,6 +6,13 @@
 package serial
 
 // Purges input and output buffers.
-func (p *serialPort) PurgeBuffers(clearRx, clearTx bool) error {
-	return purgeComm(p.fd, clearRx, clearTx)
+// Windows does not report the number of dropped bytes.
+func (p *serialPort) PurgeBuffersWithResult(clearRx, clearTx bool) (PurgeResult, error) {
+	result := PurgeResult{RxDropped: -1, TxDropped: -1}
+	if err := purgeComm(p.fd, clearRx, clearTx); err != nil {
+		return result, err
+	}
+	result.RxPurged = clearRx
+	result.TxPurged = clearTx
+	return result, nil
 }
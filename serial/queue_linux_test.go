@@ -0,0 +1,42 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinuxInputWaiting(t *testing.T) {
+	port, remote := openPtyPort(t)
+
+	if n, err := port.InputWaiting(); err != nil || n != 0 {
+		t.Errorf("expect empty input queue, got %d and %v", n, err)
+	}
+
+	remote.Write([]byte{1, 2, 3, 4, 5})
+	time.Sleep(time.Millisecond * 20)
+
+	if n, err := port.InputWaiting(); err != nil || n != 5 {
+		t.Errorf("expect 5 bytes waiting, got %d and %v", n, err)
+	}
+
+	port.Read(make([]byte, 2))
+	if n, err := port.InputWaiting(); err != nil || n != 3 {
+		t.Errorf("expect 3 bytes waiting, got %d and %v", n, err)
+	}
+}
+
+// A pty passes written data to the other side immediately,
+// so only the call itself can be checked.
+func TestLinuxOutputWaiting(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	port.Write([]byte{1, 2, 3})
+	if n, err := port.OutputWaiting(); err != nil || n < 0 {
+		t.Errorf("expect output queue size, got %d and %v", n, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Queue introspection is not supported on target OS.
+
+func (p *serialPort) InputWaiting() (int, error) {
+	return 0, ErrNotImplementedOnOS
+}
+
+func (p *serialPort) OutputWaiting() (int, error) {
+	return 0, ErrNotImplementedOnOS
+}
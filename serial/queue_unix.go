@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// InputWaiting returns the number of received bytes that have not been read yet (FIONREAD).
+// With `OpenOptions.ReportLineErrors` the count includes the bytes of PARMRK marks.
+func (p *serialPort) InputWaiting() (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+	var n int32
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, kFIONREAD, uintptr(unsafe.Pointer(&n)))
+	})
+	return int(n), err
+}
+
+// OutputWaiting returns the number of written bytes that have not been transmitted yet (TIOCOUTQ).
+// Some drivers don't count the bytes in the FIFO of the UART.
+func (p *serialPort) OutputWaiting() (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+	var n int32
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, unix.TIOCOUTQ, uintptr(unsafe.Pointer(&n)))
+	})
+	return int(n), err
+}
@@ -0,0 +1,34 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+// InputWaiting returns the number of received bytes that have not been read yet.
+// It is taken from ClearCommError, which also resets the error flags of the port.
+func (p *serialPort) InputWaiting() (int, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return 0, ErrInvalidOrNilPort
+	}
+	_, stat, err := clearCommError(p.fd)
+	if err != nil {
+		return 0, err
+	}
+	return int(stat.cbInQue), nil
+}
+
+// OutputWaiting returns the number of written bytes that have not been transmitted yet.
+// It is taken from ClearCommError, which also resets the error flags of the port.
+func (p *serialPort) OutputWaiting() (int, error) {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return 0, ErrInvalidOrNilPort
+	}
+	_, stat, err := clearCommError(p.fd)
+	if err != nil {
+		return 0, err
+	}
+	return int(stat.cbOutQue), nil
+}
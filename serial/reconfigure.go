@@ -0,0 +1,20 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// ReconfigureMode tells Reconfigure what to do with pending data.
+type ReconfigureMode int
+
+const (
+	// Apply the new settings immediately. Bytes in flight may be garbled.
+	RECONFIGURE_NOW ReconfigureMode = iota
+
+	// Wait until all written data has been transmitted, then apply the new settings.
+	RECONFIGURE_DRAIN
+
+	// Like RECONFIGURE_DRAIN, but also discard the received data that has not been read yet.
+	RECONFIGURE_FLUSH
+)
@@ -0,0 +1,42 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Applies the options with TIOCSETA, TIOCSETAW or TIOCSETAF.
+// A non-standard baud rate is then set with IOSSIOSPEED, like Open does.
+func (p *serialPort) applyOptions(options OpenOptions, mode ReconfigureMode) error {
+	terminalOptions, err := convertOptions(options)
+	if err != nil {
+		return err
+	}
+
+	var req uintptr
+	switch mode {
+	case RECONFIGURE_NOW:
+		req = kTIOCSETA
+	case RECONFIGURE_DRAIN:
+		req = kTIOCSETAW
+	case RECONFIGURE_FLUSH:
+		req = kTIOCSETAF
+	default:
+		return &OptionError{Field: "ReconfigureMode", Value: mode}
+	}
+
+	// The draining requests wait for the output, see `waitControl`.
+	control := p.waitControl
+	if mode == RECONFIGURE_NOW {
+		control = p.control
+	}
+	return control(func(fd uintptr) error {
+		if err := setTermiosWith(fd, req, terminalOptions); err != nil {
+			return err
+		}
+		if !IsStandardBaudRate(options.BaudRate) {
+			return setSpeed(fd, options.BaudRate)
+		}
+		return nil
+	})
+}
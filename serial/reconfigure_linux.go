@@ -0,0 +1,38 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Applies the options with TCSETS2, TCSETSW2 or TCSETSF2,
+// so the drain, the flush and the new settings are done by a single ioctl.
+func (p *serialPort) applyOptions(options OpenOptions, mode ReconfigureMode) error {
+	t2, err := makeTermios2(options)
+	if err != nil {
+		return err
+	}
+
+	var req uintptr
+	switch mode {
+	case RECONFIGURE_NOW:
+		return p.setTermios2(unix.TCSETS2, t2)
+	case RECONFIGURE_DRAIN:
+		req = unix.TCSETSW2
+	case RECONFIGURE_FLUSH:
+		req = unix.TCSETSF2
+	default:
+		return &OptionError{Field: "ReconfigureMode", Value: mode}
+	}
+
+	// The draining requests wait for the output, see `waitControl`.
+	return p.waitControl(func(fd uintptr) error {
+		return ioctl(fd, req, uintptr(unsafe.Pointer(t2)))
+	})
+}
@@ -0,0 +1,83 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+	"golang.org/x/sys/unix"
+)
+
+// The master side of a pty reports the termios of the slave side.
+func TestLinuxReconfigure(t *testing.T) {
+	master, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	opt.BaudRate = 115200
+	opt.ParityMode = serial.PARITY_EVEN
+	opt.DataBits = 7
+	opt.StopBits = 2
+	opt.FlowControl = serial.FLOW_CONTROL_RTSCTS
+	if err := port.Reconfigure(opt, serial.RECONFIGURE_DRAIN); err != nil {
+		t.Fatalf("reconfigure error: %s", err)
+	}
+
+	t2, err := unix.IoctlGetTermios(int(master.Fd()), unix.TCGETS2)
+	if err != nil {
+		t.Fatalf("TCGETS2 error: %s", err)
+	}
+	if t2.Ispeed != 115200 || t2.Ospeed != 115200 {
+		t.Errorf("expect 115200 baud, got %d/%d", t2.Ispeed, t2.Ospeed)
+	}
+	// A pty forces CS8 and no parity, so only the other bits are checked.
+	want := uint32(unix.CSTOPB | unix.CRTSCTS)
+	if t2.Cflag&want != want {
+		t.Errorf("expect c_cflag bits %#x, got %#x", want, t2.Cflag)
+	}
+}
+
+func TestLinuxReconfigureFlush(t *testing.T) {
+	port, remote := openPtyPort(t)
+
+	remote.Write([]byte{1, 2, 3})
+	time.Sleep(time.Millisecond * 20)
+
+	opt := newPtyOpenOptions("")
+	opt.InterCharacterTimeout = 50
+	if err := port.Reconfigure(opt, serial.RECONFIGURE_FLUSH); err != nil {
+		t.Fatalf("reconfigure error: %s", err)
+	}
+
+	// The pending bytes are gone and the new read timeout is in effect.
+	start := time.Now()
+	n, _ := port.Read(make([]byte, 8))
+	if n != 0 {
+		t.Errorf("expect no bytes after flush, got %d", n)
+	}
+	checkDuration(t, time.Since(start), time.Millisecond*50, linuxTimeoutAccuracy)
+}
+
+func TestLinuxReconfigureInvalid(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	opt := newPtyOpenOptions("")
+	opt.DataBits = 9
+	if err := port.Reconfigure(opt, serial.RECONFIGURE_NOW); err == nil {
+		t.Errorf("expect an error for invalid DataBits")
+	}
+	opt.DataBits = 8
+	if err := port.Reconfigure(opt, serial.ReconfigureMode(-1)); err == nil {
+		t.Errorf("expect an error for invalid mode")
+	}
+}
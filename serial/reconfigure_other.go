@@ -0,0 +1,13 @@
+//go:build !windows && !linux && !darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Reconfiguration is not supported on target OS.
+func (p *serialPort) Reconfigure(_ OpenOptions, _ ReconfigureMode) error {
+	return ErrNotImplementedOnOS
+}
@@ -0,0 +1,42 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Reconfigure changes the settings of the open port without closing it,
+// so the modem lines are left as they are and no data is lost.
+//
+// All settings are applied with a single ioctl call. The PortName and Rs485*
+// options are ignored, use SetRS485 instead. The read timeout options are used
+// by Read until the first SetTimeouts call, the same way as after Open.
+//
+// RECONFIGURE_DRAIN and RECONFIGURE_FLUSH wait for the output like Drain does,
+// so Close from another goroutine does not wait for a pending Reconfigure.
+// Reconfigure should not be called concurrently with Read or Write.
+func (p *serialPort) Reconfigure(options OpenOptions, mode ReconfigureMode) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.applyOptions(options, mode); err != nil {
+		return p.wrapError(err)
+	}
+
+	p.mu.Lock()
+	p.policy = policyFromOpenOptions(options)
+	p.mu.Unlock()
+
+	p.markErrors = options.ReportLineErrors
+	p.decoder = parmrkDecoder{}
+	p.countersFailed = false
+	p.initLineErrorCounts()
+
+	return p.setupFlowControl(options)
+}
@@ -0,0 +1,45 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "syscall"
+
+// Reconfigure changes the settings of the open port without closing it,
+// so the modem lines are left as they are and no data is lost.
+//
+// The settings are applied with a single SetCommState call. The PortName and Rs485*
+// options are ignored. The read timeout options are applied too, unless SetTimeouts
+// has been called.
+func (p *serialPort) Reconfigure(options OpenOptions, mode ReconfigureMode) error {
+	if p == nil || p.fd == syscall.Handle(0) || p.fd == syscall.InvalidHandle {
+		return ErrInvalidOrNilPort
+	}
+
+	switch mode {
+	case RECONFIGURE_NOW:
+	case RECONFIGURE_DRAIN:
+		if err := p.Drain(); err != nil {
+			return err
+		}
+	case RECONFIGURE_FLUSH:
+		if err := p.Drain(); err != nil {
+			return err
+		}
+		if err := purgeComm(p.fd, true, false); err != nil {
+			return err
+		}
+	default:
+		return &OptionError{Field: "ReconfigureMode", Value: mode}
+	}
+
+	if err := setCommState(p.fd, options); err != nil {
+		return err
+	}
+	if p.useTimeouts {
+		return nil
+	}
+	return setCommTimeouts(p.fd, ctoFromOpenOptions(options))
+}
@@ -0,0 +1,62 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// RS485Config mirrors struct serial_rs485 of Linux.
+// Which fields are honored depends on the driver and the kernel version.
+type RS485Config struct {
+	Enabled bool
+
+	// Logic level of RTS while sending and after sending, true for high.
+	RtsHighDuringSend bool
+	RtsHighAfterSend  bool
+
+	// Receive data while sending, e.g. to check for collisions.
+	RxDuringTx bool
+
+	// Enable the bus termination resistor, if the port can switch it.
+	TerminateBus bool
+
+	// Use RS422 instead of RS485 on ports that support both.
+	RS422 bool
+
+	// RTS delays around sending, in milliseconds.
+	DelayRtsBeforeSend int
+	DelayRtsAfterSend  int
+
+	// 9-bit addressing mode (ADDRB).
+	AddressMode bool
+
+	// With AddressMode, receive only frames for ReceiveAddress.
+	ReceiveAddressEnabled bool
+	ReceiveAddress        byte
+
+	// With AddressMode, send frames to DestinationAddress.
+	DestinationAddressEnabled bool
+	DestinationAddress        byte
+}
+
+func (c RS485Config) validate() error {
+	if c.DelayRtsBeforeSend < 0 {
+		return &OptionError{Field: "DelayRtsBeforeSend", Value: c.DelayRtsBeforeSend}
+	}
+	if c.DelayRtsAfterSend < 0 {
+		return &OptionError{Field: "DelayRtsAfterSend", Value: c.DelayRtsAfterSend}
+	}
+	return nil
+}
+
+// Returns the RS485 settings of the Rs485* options.
+func (options OpenOptions) rs485Config() RS485Config {
+	return RS485Config{
+		Enabled:            options.Rs485Enable,
+		RtsHighDuringSend:  options.Rs485RtsHighDuringSend,
+		RtsHighAfterSend:   options.Rs485RtsHighAfterSend,
+		RxDuringTx:         options.Rs485RxDuringTx,
+		DelayRtsBeforeSend: options.Rs485DelayRtsBeforeSend,
+		DelayRtsAfterSend:  options.Rs485DelayRtsAfterSend,
+	}
+}
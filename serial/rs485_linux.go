@@ -0,0 +1,81 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "unsafe"
+
+// SetRS485 applies the RS485 settings with TIOCSRS485.
+func (p *serialPort) SetRS485(config RS485Config) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+	rs485 := makeSerialRS485(config)
+	return p.control(func(fd uintptr) error {
+		return ioctl(fd, tIOCSRS485, uintptr(unsafe.Pointer(&rs485)))
+	})
+}
+
+// RS485 returns the RS485 settings of the port read with TIOCGRS485.
+func (p *serialPort) RS485() (RS485Config, error) {
+	if p == nil || p.File == nil {
+		return RS485Config{}, ErrInvalidOrNilPort
+	}
+	var rs485 serial_rs485
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, tIOCGRS485, uintptr(unsafe.Pointer(&rs485)))
+	})
+	if err != nil {
+		return RS485Config{}, err
+	}
+	return rs485Config(&rs485), nil
+}
+
+var rs485Flags = []struct {
+	flag  uint32
+	field func(c *RS485Config) *bool
+}{
+	{sER_RS485_ENABLED, func(c *RS485Config) *bool { return &c.Enabled }},
+	{sER_RS485_RTS_ON_SEND, func(c *RS485Config) *bool { return &c.RtsHighDuringSend }},
+	{sER_RS485_RTS_AFTER_SEND, func(c *RS485Config) *bool { return &c.RtsHighAfterSend }},
+	{sER_RS485_RX_DURING_TX, func(c *RS485Config) *bool { return &c.RxDuringTx }},
+	{sER_RS485_TERMINATE_BUS, func(c *RS485Config) *bool { return &c.TerminateBus }},
+	{sER_RS485_ADDRB, func(c *RS485Config) *bool { return &c.AddressMode }},
+	{sER_RS485_ADDR_RECV, func(c *RS485Config) *bool { return &c.ReceiveAddressEnabled }},
+	{sER_RS485_ADDR_DEST, func(c *RS485Config) *bool { return &c.DestinationAddressEnabled }},
+	{sER_RS485_MODE_RS422, func(c *RS485Config) *bool { return &c.RS422 }},
+}
+
+// The config must be validated, negative delays would wrap around.
+func makeSerialRS485(config RS485Config) serial_rs485 {
+	rs485 := serial_rs485{
+		delay_rts_before_send: uint32(config.DelayRtsBeforeSend),
+		delay_rts_after_send:  uint32(config.DelayRtsAfterSend),
+		addr_recv:             config.ReceiveAddress,
+		addr_dest:             config.DestinationAddress,
+	}
+	for _, f := range rs485Flags {
+		if *f.field(&config) {
+			rs485.flags |= f.flag
+		}
+	}
+	return rs485
+}
+
+func rs485Config(rs485 *serial_rs485) RS485Config {
+	config := RS485Config{
+		DelayRtsBeforeSend: int(rs485.delay_rts_before_send),
+		DelayRtsAfterSend:  int(rs485.delay_rts_after_send),
+		ReceiveAddress:     rs485.addr_recv,
+		DestinationAddress: rs485.addr_dest,
+	}
+	for _, f := range rs485Flags {
+		*f.field(&config) = rs485.flags&f.flag != 0
+	}
+	return config
+}
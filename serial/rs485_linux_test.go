@@ -0,0 +1,36 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+func TestLinuxRS485NegativeDelay(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.Rs485Enable = true
+	opt.Rs485DelayRtsBeforeSend = -1
+	if port, err := serial.Open(opt); err == nil {
+		port.Close()
+		t.Errorf("expect an error for negative Rs485DelayRtsBeforeSend")
+	}
+}
+
+// A pty has no RS485 support, so the ioctl errors are passed through.
+func TestLinuxRS485Unsupported(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	if err := port.SetRS485(serial.RS485Config{Enabled: true}); err == nil {
+		t.Errorf("expect an error from SetRS485 on a pty")
+	}
+	if _, err := port.RS485(); err == nil {
+		t.Errorf("expect an error from RS485 on a pty")
+	}
+}
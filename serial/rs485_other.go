@@ -0,0 +1,18 @@
+//go:build !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// RS485 settings are not supported on target OS.
+
+func (p *serialPort) SetRS485(_ RS485Config) error {
+	return ErrNotImplementedOnOS
+}
+
+func (p *serialPort) RS485() (RS485Config, error) {
+	return RS485Config{}, ErrNotImplementedOnOS
+}
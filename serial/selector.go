@@ -0,0 +1,160 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of port selectors accepted by Open in place of a device path.
+//
+//	usb:VID[:PID[:SERIAL]]  e.g. "usb:0403:6001:A1B2C3", VID and PID are hex
+//	by-id:NAME              e.g. "by-id:usb-FTDI_FT232R_USB_UART_A1B2C3-if00-port0"
+//	by-path:NAME            e.g. "by-path:pci-0000:00:14.0-usb-0:2:1.0-port0"
+const (
+	SELECTOR_USB     = "usb:"
+	SELECTOR_BY_ID   = "by-id:"
+	SELECTOR_BY_PATH = "by-path:"
+)
+
+// SelectorError is returned by Open when a port selector matches no port or more than one port.
+type SelectorError struct {
+	Selector   string
+	Matches    []PortInfo // Ports matched by an ambiguous selector.
+	Candidates []PortInfo // All ports found, when nothing matched.
+}
+
+func (e *SelectorError) Error() string {
+	if len(e.Matches) > 0 {
+		return fmt.Sprintf("port selector %q is ambiguous, it matches: %s", e.Selector, joinPorts(e.Matches))
+	}
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("port selector %q matches no port, no ports found", e.Selector)
+	}
+	return fmt.Sprintf("port selector %q matches no port, available: %s", e.Selector, joinPorts(e.Candidates))
+}
+
+// Unwrap makes a selector that matches no port match ErrPortNotFound.
+func (e *SelectorError) Unwrap() error {
+	if len(e.Matches) > 0 {
+		return nil
+	}
+	return ErrPortNotFound
+}
+
+func joinPorts(ports []PortInfo) string {
+	s := make([]string, len(ports))
+	for i, p := range ports {
+		s[i] = p.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+type portSelector struct {
+	kind   string // one of SELECTOR_*
+	vid    uint16
+	pid    *uint16
+	serial *string
+	name   string // alias name for by-id and by-path
+}
+
+// Parses a port selector. Returns false if the name is not a selector.
+// An invalid selector gives *OptionError.
+func parsePortSelector(s string) (portSelector, bool, error) {
+	switch {
+	case strings.HasPrefix(s, SELECTOR_USB):
+		// The serial number may contain colons, so it takes the rest of the string.
+		parts := strings.SplitN(strings.TrimPrefix(s, SELECTOR_USB), ":", 3)
+		sel := portSelector{kind: SELECTOR_USB}
+		vid, err := strconv.ParseUint(parts[0], 16, 16)
+		if err != nil {
+			return sel, true, &OptionError{Field: "PortName", Value: s, Reason: "invalid vendor ID in port selector"}
+		}
+		sel.vid = uint16(vid)
+		if len(parts) > 1 {
+			pid, err := strconv.ParseUint(parts[1], 16, 16)
+			if err != nil {
+				return sel, true, &OptionError{Field: "PortName", Value: s, Reason: "invalid product ID in port selector"}
+			}
+			p := uint16(pid)
+			sel.pid = &p
+		}
+		if len(parts) > 2 {
+			sel.serial = &parts[2]
+		}
+		return sel, true, nil
+
+	case strings.HasPrefix(s, SELECTOR_BY_ID), strings.HasPrefix(s, SELECTOR_BY_PATH):
+		kind := SELECTOR_BY_ID
+		if strings.HasPrefix(s, SELECTOR_BY_PATH) {
+			kind = SELECTOR_BY_PATH
+		}
+		name := filepath.Base(strings.TrimPrefix(s, kind))
+		if name == "." || name == string(filepath.Separator) {
+			return portSelector{}, true, &OptionError{Field: "PortName", Value: s, Reason: "empty name in port selector"}
+		}
+		return portSelector{kind: kind, name: name}, true, nil
+
+	default:
+		return portSelector{}, false, nil
+	}
+}
+
+func (sel portSelector) match(p PortInfo) bool {
+	switch sel.kind {
+	case SELECTOR_USB:
+		return p.IsUSB && p.VID == sel.vid &&
+			(sel.pid == nil || p.PID == *sel.pid) &&
+			(sel.serial == nil || p.SerialNumber == *sel.serial)
+	case SELECTOR_BY_ID:
+		return hasAlias(p.ByID, sel.name)
+	case SELECTOR_BY_PATH:
+		return hasAlias(p.ByPath, sel.name)
+	}
+	return false
+}
+
+func hasAlias(aliases []string, name string) bool {
+	for _, a := range aliases {
+		if filepath.Base(a) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the device path for a port selector, or the name itself if it is not a selector.
+// The list function is called only for selectors.
+func resolvePortName(name string, list func() ([]PortInfo, error)) (string, error) {
+	sel, ok, err := parsePortSelector(name)
+	if !ok || err != nil {
+		return name, err
+	}
+
+	ports, err := list()
+	if err != nil {
+		return "", errors.Join(fmt.Errorf("can't resolve port selector %q", name), err)
+	}
+
+	var matches []PortInfo
+	for _, p := range ports {
+		if sel.match(p) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0].Name, nil
+	case 0:
+		return "", &SelectorError{Selector: name, Candidates: ports}
+	default:
+		return "", &SelectorError{Selector: name, Matches: matches}
+	}
+}
@@ -0,0 +1,78 @@
+package serial
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolvePortName(t *testing.T) {
+	ports := []PortInfo{
+		{Name: "/dev/ttyS0", Driver: "serial8250"},
+		{Name: "/dev/ttyUSB0", Driver: "ftdi_sio", IsUSB: true, VID: 0x0403, PID: 0x6001, SerialNumber: "A1",
+			ByID:   []string{"/dev/serial/by-id/usb-FTDI_A1-if00-port0"},
+			ByPath: []string{"/dev/serial/by-path/pci-0000:00:14.0-usb-0:2:1.0-port0"}},
+		{Name: "/dev/ttyUSB1", Driver: "ftdi_sio", IsUSB: true, VID: 0x0403, PID: 0x6001, SerialNumber: "B:2"},
+		{Name: "/dev/ttyACM0", Driver: "cdc_acm", IsUSB: true, VID: 0x2341, PID: 0x0043},
+	}
+	list := func() ([]PortInfo, error) { return ports, nil }
+
+	testCases := []struct {
+		name   string
+		expect string
+		err    string
+	}{
+		{"/dev/ttyS0", "/dev/ttyS0", ""},
+		{"COM3", "COM3", ""},
+		{"usb:0403:6001:A1", "/dev/ttyUSB0", ""},
+		{"usb:0403:6001:B:2", "/dev/ttyUSB1", ""},
+		{"usb:2341", "/dev/ttyACM0", ""},
+		{"usb:2341:0043", "/dev/ttyACM0", ""},
+		{"by-id:usb-FTDI_A1-if00-port0", "/dev/ttyUSB0", ""},
+		{"by-id:/dev/serial/by-id/usb-FTDI_A1-if00-port0", "/dev/ttyUSB0", ""},
+		{"by-path:pci-0000:00:14.0-usb-0:2:1.0-port0", "/dev/ttyUSB0", ""},
+		{"usb:0403:6001", "", "ambiguous, it matches: /dev/ttyUSB0 (ftdi_sio, usb 0403:6001, serial A1); /dev/ttyUSB1"},
+		{"usb:0403:6001:C3", "", "matches no port, available: /dev/ttyS0 (serial8250); /dev/ttyUSB0"},
+		{"by-id:missing", "", "matches no port"},
+		{"usb:xyz", "", "invalid vendor ID"},
+		{"usb:0403:", "", "invalid product ID"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := resolvePortName(testCase.name, list)
+			if testCase.err != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.err) {
+					t.Errorf("expected error containing %q, but got %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != testCase.expect {
+				t.Errorf("expected %q, but got %q", testCase.expect, result)
+			}
+		})
+	}
+
+	var selErr *SelectorError
+	if _, err := resolvePortName("usb:0403:6001", list); !errors.As(err, &selErr) || len(selErr.Matches) != 2 {
+		t.Errorf("expected a SelectorError with 2 matches, but got %v", err)
+	}
+
+	var optErr *OptionError
+	if _, err := resolvePortName("usb:xyz", list); !errors.As(err, &optErr) || optErr.Field != "PortName" {
+		t.Errorf("expected an OptionError for PortName, but got %v", err)
+	}
+}
+
+func TestResolvePortNameListOnlyForSelectors(t *testing.T) {
+	list := func() ([]PortInfo, error) { return nil, errors.New("no sysfs") }
+	if name, err := resolvePortName("/dev/ttyUSB0", list); err != nil || name != "/dev/ttyUSB0" {
+		t.Errorf("expected the name unchanged, but got %q and %v", name, err)
+	}
+	if _, err := resolvePortName("usb:0403", list); err == nil {
+		t.Errorf("expected an error")
+	}
+}
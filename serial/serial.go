@@ -35,6 +35,12 @@ var _ = io.ReadWriteCloser((*Port)(nil))
 // Open creates a `serial.Port` based on the supplied options struct.
 // It implements io.ReadWriteCloser interface.
 func Open(options OpenOptions) (*Port, error) {
+	name, err := resolvePortName(options.PortName, ListPorts)
+	if err != nil {
+		return nil, err
+	}
+	options.PortName = name
+
 	// Redirect to the OS-specific function.
 	port, err := openInternal(options)
 	if err != nil {
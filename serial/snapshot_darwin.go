@@ -0,0 +1,51 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// Settings of the port before Open changed them, to be restored on Close.
+// Nil fields are not supported by the driver.
+type portSnapshot struct {
+	termios *termios
+	modem   *ModemStatus
+}
+
+// Takes the snapshot of termios and modem lines.
+func (p *serialPort) takeSnapshot() {
+	s := &portSnapshot{modem: p.snapshotModemLines()}
+	t := &termios{}
+	err := p.control(func(fd uintptr) error {
+		return ioctl(fd, kTIOCGETA, uintptr(unsafe.Pointer(t)))
+	})
+	if err == nil {
+		s.termios = t
+	}
+	p.snapshot = s
+}
+
+// Restores the snapshot. Close drains the output first.
+func (p *serialPort) restoreSnapshot() error {
+	s := p.snapshot
+	if s == nil {
+		return nil
+	}
+	p.snapshot = nil
+
+	var err error
+	if s.termios != nil {
+		err = p.control(func(fd uintptr) error {
+			return setTermios(fd, s.termios)
+		})
+	}
+	if s.modem != nil {
+		err = errors.Join(err, p.restoreModemLines(*s.modem))
+	}
+	return err
+}
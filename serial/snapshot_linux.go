@@ -0,0 +1,56 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// Settings of the port before Open changed them, to be restored on Close.
+// Nil fields are not supported by the driver.
+type portSnapshot struct {
+	termios *termios2
+	rs485   *RS485Config
+	modem   *ModemStatus
+}
+
+// Takes the snapshot of termios2, RS485 and modem lines.
+func (p *serialPort) takeSnapshot() {
+	s := &portSnapshot{modem: p.snapshotModemLines()}
+	if t2, err := p.getTermios2(); err == nil {
+		s.termios = t2
+	}
+	if config, err := p.RS485(); err == nil {
+		s.rs485 = &config
+	}
+	p.snapshot = s
+}
+
+// Restores the snapshot. Close drains the output first.
+func (p *serialPort) restoreSnapshot() error {
+	s := p.snapshot
+	if s == nil {
+		return nil
+	}
+	p.snapshot = nil
+
+	var err error
+	if s.rs485 != nil {
+		// Drivers without RS485 support may reject even the disabled config.
+		if current, getErr := p.RS485(); getErr != nil || current != *s.rs485 {
+			err = p.SetRS485(*s.rs485)
+		}
+	}
+	if s.termios != nil {
+		err = errors.Join(err, p.setTermios2(unix.TCSETS2, s.termios))
+	}
+	if s.modem != nil {
+		err = errors.Join(err, p.restoreModemLines(*s.modem))
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sergereinov/go-serial/serial"
+	"golang.org/x/sys/unix"
+)
+
+func TestLinuxRestoreOnClose(t *testing.T) {
+	testCases := []struct {
+		name string
+		keep bool
+	}{
+		{"restore", false},
+		{"keep", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			master, name := openPty(t)
+			orig := getSlaveTermios(t, master)
+
+			opt := newPtyOpenOptions(name)
+			opt.BaudRate = 115200
+			opt.KeepSettingsOnClose = testCase.keep
+			port, err := serial.Open(opt)
+			if err != nil {
+				t.Fatalf("open error: %s", err)
+			}
+			if err := port.Close(); err != nil {
+				t.Fatalf("close error: %s", err)
+			}
+
+			after := getSlaveTermios(t, master)
+			switch {
+			case testCase.keep && after.Ospeed != 115200:
+				t.Errorf("expect 115200 baud to be kept, got %d", after.Ospeed)
+			case !testCase.keep && *after != *orig:
+				t.Errorf("expect termios %+v to be restored, got %+v", *orig, *after)
+			}
+		})
+	}
+}
+
+// The master side of a pty reports the termios of the slave side.
+func getSlaveTermios(t *testing.T, master *os.File) *unix.Termios {
+	t.Helper()
+	t2, err := unix.IoctlGetTermios(int(master.Fd()), unix.TCGETS2)
+	if err != nil {
+		t.Fatalf("TCGETS2 error: %s", err)
+	}
+	return t2
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "errors"
+
+// Returns the state of the output modem lines, or nil if the driver can't tell it.
+func (p *serialPort) snapshotModemLines() *ModemStatus {
+	status, err := p.GetModemStatus()
+	if err != nil {
+		return nil
+	}
+	status &^= MODEM_INPUTS
+	return &status
+}
+
+func (p *serialPort) restoreModemLines(status ModemStatus) error {
+	return errors.Join(
+		p.SetDTR(status.Has(MODEM_DTR)),
+		p.SetRTS(status.Has(MODEM_RTS)))
+}
@@ -0,0 +1,23 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+// Takes the snapshot of the DCB, which also holds the DTR and RTS modes.
+func (p *serialPort) takeSnapshot() {
+	if params, err := getCommState(p.fd); err == nil {
+		p.snapshot = params
+	}
+}
+
+// Restores the DCB taken by takeSnapshot.
+func (p *serialPort) restoreSnapshot() error {
+	params := p.snapshot
+	if params == nil {
+		return nil
+	}
+	p.snapshot = nil
+	return setCommStateDCB(p.fd, params)
+}
@@ -0,0 +1,144 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Port specs are compact strings for command lines and config files:
+//
+//	[NAME:]BAUD[,FRAMING][,FLOW]
+//
+//	NAME     the port name, which may contain colons itself, e.g. "usb:0403:6001:A1B2C3"
+//	BAUD     the baud rate, e.g. "115200"
+//	FRAMING  data bits, parity and stop bits, e.g. "8N1" or "7E2",
+//	         parity is one of N (none), O (odd), E (even), M (mark) and S (space)
+//	FLOW     one of "none", "rtscts", "xonxoff" and "dtrdsr"
+//
+// E.g. "/dev/ttyUSB0:115200,8E1,rtscts" or "COM3:9600".
+// The settings follow the last colon, so they can't be omitted when NAME is given.
+// After a "usb:" selector FRAMING is required too, e.g. "usb:0403:6001:9600,8N1",
+// otherwise "usb:0403:6001" would be read as the vendor ID 0403 at 6001 baud.
+
+var parityLetters = map[ParityMode]byte{
+	PARITY_NONE:  'N',
+	PARITY_ODD:   'O',
+	PARITY_EVEN:  'E',
+	PARITY_MARK:  'M',
+	PARITY_SPACE: 'S',
+}
+
+var flowControlNames = map[FlowControl]string{
+	FLOW_CONTROL_NONE:    "none",
+	FLOW_CONTROL_RTSCTS:  "rtscts",
+	FLOW_CONTROL_XONXOFF: "xonxoff",
+	FLOW_CONTROL_DTRDSR:  "dtrdsr",
+}
+
+// ParseOptions parses a port spec like "/dev/ttyUSB0:115200,8E1,rtscts".
+// FRAMING is 8N1 and FLOW is none when omitted. The read options are set
+// to MinimumReadSize = 1, so Read waits for data and returns what has arrived.
+//
+// An invalid spec gives *OptionError.
+func ParseOptions(spec string) (OpenOptions, error) {
+	options := OpenOptions{
+		DataBits:        8,
+		StopBits:        1,
+		ParityMode:      PARITY_NONE,
+		MinimumReadSize: 1,
+	}
+
+	settings := spec
+	if i := strings.LastIndexByte(spec, ':'); i >= 0 {
+		options.PortName, settings = spec[:i], spec[i+1:]
+	}
+
+	fields := strings.Split(settings, ",")
+	if strings.HasPrefix(options.PortName, SELECTOR_USB) && len(fields) < 2 {
+		return OpenOptions{}, &OptionError{Field: "spec", Value: spec,
+			Reason: "settings after a usb: selector need FRAMING, e.g. usb:0403:6001:9600,8N1"}
+	}
+	baud, err := strconv.ParseUint(fields[0], 10, 32)
+	if err != nil || baud == 0 {
+		return OpenOptions{}, &OptionError{Field: "BaudRate", Value: fields[0]}
+	}
+	options.BaudRate = uint(baud)
+
+	var framing, flow bool
+	for _, field := range fields[1:] {
+		switch {
+		case !flow && parseFlowControl(field, &options):
+			flow = true
+		case !framing && parseFraming(field, &options):
+			framing = true
+		default:
+			return OpenOptions{}, &OptionError{Field: "spec", Value: field, Reason: "unknown or repeated setting"}
+		}
+	}
+
+	if err := options.Validate(); err != nil {
+		return OpenOptions{}, err
+	}
+	return options, nil
+}
+
+// Parses FRAMING like "8N1" into the options.
+func parseFraming(s string, options *OpenOptions) bool {
+	if len(s) != 3 || s[0] < '5' || s[0] > '8' || (s[2] != '1' && s[2] != '2') {
+		return false
+	}
+	upper := strings.ToUpper(s)
+	for mode, letter := range parityLetters {
+		if upper[1] == letter {
+			options.DataBits = uint(s[0] - '0')
+			options.ParityMode = mode
+			options.StopBits = uint(s[2] - '0')
+			return true
+		}
+	}
+	return false
+}
+
+// Parses FLOW like "rtscts" into the options.
+func parseFlowControl(s string, options *OpenOptions) bool {
+	for mode, name := range flowControlNames {
+		if strings.EqualFold(s, name) {
+			options.FlowControl = mode
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the port spec of the options, e.g. "/dev/ttyUSB0:115200,8E1,rtscts",
+// which ParseOptions turns back into the same name, baud rate, framing and flow control.
+// The other options are not part of the spec.
+func (o OpenOptions) String() string {
+	var b strings.Builder
+	if o.PortName != "" {
+		b.WriteString(o.PortName)
+		b.WriteByte(':')
+	}
+
+	parity, ok := parityLetters[o.ParityMode]
+	if !ok {
+		parity = '?'
+	}
+	fmt.Fprintf(&b, "%d,%d%c%d", o.BaudRate, o.DataBits, parity, o.StopBits)
+
+	if fc := o.flowControl(); fc != FLOW_CONTROL_NONE {
+		name, ok := flowControlNames[fc]
+		if !ok {
+			name = strconv.Itoa(int(fc))
+		}
+		b.WriteByte(',')
+		b.WriteString(name)
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package serial
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected OpenOptions
+		str      string // expected String(), if not the same as spec
+	}{
+		{
+			spec:     "/dev/ttyUSB0:115200,8E1,rtscts",
+			expected: OpenOptions{PortName: "/dev/ttyUSB0", BaudRate: 115200, DataBits: 8, ParityMode: PARITY_EVEN, StopBits: 1, FlowControl: FLOW_CONTROL_RTSCTS},
+		},
+		{
+			spec:     "COM3:9600",
+			expected: OpenOptions{PortName: "COM3", BaudRate: 9600, DataBits: 8, StopBits: 1},
+			str:      "COM3:9600,8N1",
+		},
+		{
+			spec:     "usb:0403:6001:A1:19200,xonxoff,7s2",
+			expected: OpenOptions{PortName: "usb:0403:6001:A1", BaudRate: 19200, DataBits: 7, ParityMode: PARITY_SPACE, StopBits: 2, FlowControl: FLOW_CONTROL_XONXOFF},
+			str:      "usb:0403:6001:A1:19200,7S2,xonxoff",
+		},
+		{
+			spec:     "250000,5M1,dtrdsr",
+			expected: OpenOptions{BaudRate: 250000, DataBits: 5, ParityMode: PARITY_MARK, StopBits: 1, FlowControl: FLOW_CONTROL_DTRDSR},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.spec, func(t *testing.T) {
+			options, err := ParseOptions(testCase.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			testCase.expected.MinimumReadSize = 1
+			if options != testCase.expected {
+				t.Errorf("expected %+v, but got %+v", testCase.expected, options)
+			}
+
+			str := testCase.str
+			if str == "" {
+				str = testCase.spec
+			}
+			if s := options.String(); s != str {
+				t.Errorf("expected String() %q, but got %q", str, s)
+			}
+			if again, err := ParseOptions(options.String()); err != nil || again != options {
+				t.Errorf("expected the round trip to give %+v, but got %+v, %v", options, again, err)
+			}
+		})
+	}
+}
+
+func TestParseOptionsErrors(t *testing.T) {
+	testCases := []struct {
+		spec  string
+		field string
+	}{
+		{"/dev/ttyUSB0", "BaudRate"},
+		{"/dev/ttyUSB0:", "BaudRate"},
+		{"/dev/ttyUSB0:0", "BaudRate"},
+		{"/dev/ttyUSB0:fast", "BaudRate"},
+		{"/dev/ttyUSB0:9600,9N1", "spec"},
+		{"/dev/ttyUSB0:9600,8X1", "spec"},
+		{"/dev/ttyUSB0:9600,8N1,8N1", "spec"},
+		{"/dev/ttyUSB0:9600,rtscts,none", "spec"},
+		{"/dev/ttyUSB0:9600,8N1,", "spec"},
+		{"usb:0403:6001", "spec"},
+		{"usb:10c4:ea60:0001", "spec"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.spec, func(t *testing.T) {
+			_, err := ParseOptions(testCase.spec)
+			var optErr *OptionError
+			if !errors.As(err, &optErr) || optErr.Field != testCase.field {
+				t.Errorf("expected an OptionError for %s, but got %v", testCase.field, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := OpenOptions{BaudRate: 9600, DataBits: 8, StopBits: 1, MinimumReadSize: 1}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		field  string
+		modify func(o *OpenOptions)
+	}{
+		{"BaudRate", func(o *OpenOptions) { o.BaudRate = 0 }},
+		{"InterCharacterTimeout", func(o *OpenOptions) { o.MinimumReadSize = 0 }},
+		{"DataBits", func(o *OpenOptions) { o.DataBits = 9 }},
+		{"StopBits", func(o *OpenOptions) { o.StopBits = 0 }},
+		{"ParityMode", func(o *OpenOptions) { o.ParityMode = 5 }},
+		{"FlowControl", func(o *OpenOptions) { o.FlowControl = 4 }},
+		{"DelayRtsAfterSend", func(o *OpenOptions) { o.Rs485Enable = true; o.Rs485DelayRtsAfterSend = -1 }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.field, func(t *testing.T) {
+			options := valid
+			testCase.modify(&options)
+			var optErr *OptionError
+			if err := options.Validate(); !errors.As(err, &optErr) || optErr.Field != testCase.field {
+				t.Errorf("expected an OptionError for %s, but got %v", testCase.field, err)
+			}
+		})
+	}
+}
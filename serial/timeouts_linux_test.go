@@ -0,0 +1,171 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sergereinov/go-serial/serial"
+)
+
+// Linux counterpart of the windows `TestTimeouts`.
+// See `pty_linux_test.go` for the test setup.
+//
+// One can run these tests with the following:
+//   go test -v ./... -run Timeouts
+
+const (
+	// Expected accuracy of timeouts. May depend on go runtime and OS scheduler.
+	linuxTimeoutAccuracy = time.Millisecond * 20
+)
+
+// Opens the slave side of a new pty pair as a serial port.
+// Returns the port and the master side that plays the role of the remote device.
+func openPtyPort(t *testing.T) (*serial.Port, *os.File) {
+	t.Helper()
+	master, name := openPty(t)
+	port, err := serial.Open(newPtyOpenOptions(name))
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	t.Cleanup(func() { port.Close() })
+	return port, master
+}
+
+func newPtyOpenOptions(name string) serial.OpenOptions {
+	return serial.OpenOptions{
+		PortName:              name,
+		BaudRate:              9600,
+		ParityMode:            serial.PARITY_NONE,
+		DataBits:              8,
+		StopBits:              1,
+		MinimumReadSize:       0,
+		InterCharacterTimeout: 100,
+	}
+}
+
+func checkDuration(t *testing.T, since, expect, accuracy time.Duration) {
+	t.Helper()
+	if since < expect-accuracy || since > expect+accuracy {
+		t.Errorf("expect %v +/-%v, got %v", expect, accuracy, since)
+	}
+}
+
+func TestLinuxTimeoutsWriteTotal(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	// Nobody reads the remote side, so the write stalls once the pty buffers are full.
+	data := make([]byte, 1<<20)
+	timeouts := serial.DefaultTimeouts()
+	timeouts.WriteTotal = time.Millisecond * 200
+
+	start := time.Now()
+	n, err := port.WriteWithTimeouts(data, timeouts)
+	since := time.Since(start)
+	if err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+	if n == 0 || n >= len(data) {
+		t.Errorf("expect partial write, got %d of %d bytes", n, len(data))
+	}
+	checkDuration(t, since, timeouts.WriteTotal, linuxTimeoutAccuracy)
+}
+
+func TestLinuxTimeoutsReadTotal(t *testing.T) {
+	port, _ := openPtyPort(t)
+
+	buf := make([]byte, 1) // there are no incoming bytes, so a buffer size of 1 byte is sufficient
+	timeouts := serial.DefaultTimeouts()
+	timeouts.ReadTotal = time.Millisecond * 200
+
+	start := time.Now()
+	n, err := port.ReadWithTimeouts(buf, timeouts)
+	since := time.Since(start)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	if n != 0 {
+		t.Errorf("expect no incoming bytes, got %d", n)
+	}
+	checkDuration(t, since, timeouts.ReadTotal, linuxTimeoutAccuracy)
+}
+
+func TestLinuxTimeoutsReadIntercharacter(t *testing.T) {
+	port, remote := openPtyPort(t)
+
+	const dataLen = 10
+	buf := make([]byte, dataLen+1) // make sure len(buf) > len(data) to trigger read intercharacter timeout
+	timeouts := serial.DefaultTimeouts()
+	timeouts.ReadIntercharacter = time.Millisecond * 50
+	timeouts.ReadTotal = time.Second
+
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		remote.Write(make([]byte, dataLen))
+	}()
+
+	// The data arrives after 20 ms, then the read waits for 50 ms more.
+	start := time.Now()
+	n, err := port.ReadWithTimeouts(buf, timeouts)
+	since := time.Since(start)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+	if n != dataLen {
+		t.Errorf("expect %d bytes, got %d", dataLen, n)
+	}
+	checkDuration(t, since, time.Millisecond*70, linuxTimeoutAccuracy)
+}
+
+// The old API keeps returning io.EOF on timeout, but the timeout is no longer rounded to 100 ms.
+func TestLinuxTimeoutsInterCharacterTimeoutOption(t *testing.T) {
+	_, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.InterCharacterTimeout = 150
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	start := time.Now()
+	n, err := port.Read(make([]byte, 1))
+	since := time.Since(start)
+	if n != 0 || !errors.Is(err, io.EOF) {
+		t.Errorf("expect 0 and io.EOF, got %d and %v", n, err)
+	}
+	checkDuration(t, since, time.Millisecond*150, linuxTimeoutAccuracy)
+}
+
+func TestLinuxTimeoutsMinimumReadSizeOption(t *testing.T) {
+	master, name := openPty(t)
+
+	opt := newPtyOpenOptions(name)
+	opt.MinimumReadSize = 4
+	opt.InterCharacterTimeout = 0
+	port, err := serial.Open(opt)
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	defer port.Close()
+
+	go func() {
+		master.Write([]byte{1, 2})
+		time.Sleep(time.Millisecond * 50)
+		master.Write([]byte{3, 4, 5})
+	}()
+
+	// A single call waits for the second chunk, like VMIN=4 did.
+	n, err := port.Read(make([]byte, 8))
+	if err != nil || n < 4 {
+		t.Fatalf("expect at least 4 bytes, got %d and %v", n, err)
+	}
+}
@@ -1,4 +1,4 @@
-//go:build !windows
+//go:build !windows && !linux && !darwin
 
 // ------------------------------------------
 // Created by (c) 2024 Serge Reinov.
@@ -1,3 +1,5 @@
+//go:build windows
+
 // ------------------------------------------
 // Created by (c) 2024 Serge Reinov.
 // Licensed under the Apache License, Version 2.0.
@@ -0,0 +1,321 @@
+//go:build linux || darwin
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// readPolicy describes when a Read call returns.
+// The port file is non-blocking, so all waiting is done here
+// with the help of the runtime poller instead of VMIN/VTIME.
+type readPolicy struct {
+	// Return as soon as this number of bytes has been read.
+	// Zero means that Read tries to fill the whole buffer.
+	minBytes int
+
+	// Maximum gap between two received bytes.
+	// The timer starts after the first byte. Zero disables the timer.
+	interChar time.Duration
+
+	// Maximum duration of the whole Read call. Zero disables the timer.
+	total time.Duration
+
+	// Report an empty read as io.EOF, the same way as the old VMIN/VTIME code did.
+	eofOnTimeout bool
+}
+
+// Emulates VMIN/VTIME semantics of the `InterCharacterTimeout` and
+// `MinimumReadSize` options, but without rounding to 100 ms.
+func policyFromOpenOptions(options OpenOptions) readPolicy {
+	timeout := time.Duration(options.InterCharacterTimeout) * time.Millisecond
+	if options.MinimumReadSize == 0 {
+		// Return as soon as any data arrives or when the timeout elapses.
+		return readPolicy{minBytes: 1, total: timeout, eofOnTimeout: true}
+	}
+	return readPolicy{
+		minBytes:     int(options.MinimumReadSize),
+		interChar:    timeout,
+		eofOnTimeout: true,
+	}
+}
+
+// Mirrors the behavior of windows COMMTIMEOUTS.
+func policyFromTimeouts(timeouts Timeouts) readPolicy {
+	return readPolicy{
+		interChar: timeouts.ReadIntercharacter,
+		total:     timeouts.ReadTotal,
+	}
+}
+
+// Returns the point in time at which the current wait for data should end.
+// Zero time means no deadline.
+func (rp readPolicy) deadline(start, lastByte time.Time) time.Time {
+	var d time.Time
+	if rp.total > 0 {
+		d = start.Add(rp.total)
+	}
+	if rp.interChar > 0 && !lastByte.IsZero() {
+		if ic := lastByte.Add(rp.interChar); d.IsZero() || ic.Before(d) {
+			d = ic
+		}
+	}
+	return d
+}
+
+// Sets communication timeouts for next IO operations.
+// After the first call the `InterCharacterTimeout` and `MinimumReadSize`
+// options are no longer used.
+func (p *serialPort) SetTimeouts(timeouts Timeouts) error {
+	if p == nil || p.File == nil {
+		return ErrInvalidOrNilPort
+	}
+	p.mu.Lock()
+	p.timeouts = timeouts
+	p.useTimeouts = true
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *serialPort) readPolicy() readPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.useTimeouts {
+		return policyFromTimeouts(p.timeouts)
+	}
+	return p.policy
+}
+
+func (p *serialPort) writeTotal() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.useTimeouts {
+		return p.timeouts.WriteTotal
+	}
+	return 0
+}
+
+// Returns a context that is done when the write timeout or the write deadline expires,
+// for the waits of write operations that are not done by Write.
+func (p *serialPort) writeContext() (context.Context, context.CancelFunc) {
+	deadline, _ := p.userWriteDeadline()
+	if total := p.writeTotal(); total > 0 {
+		deadline = earliest(deadline, time.Now().Add(total))
+	}
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// Read reads data according to the current timeouts.
+//
+// When the timeouts are set by SetTimeouts, an expired timeout is not an error,
+// just like on windows: Read returns the number of bytes received so far.
+func (p *serialPort) Read(buf []byte) (int, error) {
+	n, err := p.read(context.Background(), buf)
+	return n, p.wrapError(err)
+}
+
+func (p *serialPort) read(ctx context.Context, buf []byte) (int, error) {
+	if p != nil && p.markErrors {
+		// The status buffer is kept on the port, so Read doesn't allocate it each time.
+		if cap(p.statusBuf) < len(buf) {
+			p.statusBuf = make([]LineError, len(buf))
+		}
+		status := p.statusBuf[:len(buf)]
+		n, overruns, err := p.readWithStatus(ctx, buf, status)
+		if err == nil {
+			err = lineStatusError(status, n, overruns)
+		}
+		return n, err
+	}
+	return p.readTimed(ctx, buf, p.readPolicy(), &readTimers{start: time.Now()})
+}
+
+// The points in time the read timeouts are counted from.
+type readTimers struct {
+	start    time.Time // the start of the read, for the total timeout
+	lastByte time.Time // the last received byte, for the intercharacter timeout
+}
+
+// Reads the data without decoding PARMRK marks.
+// The timers are updated, so a read can be continued with the same deadlines.
+func (p *serialPort) readTimed(ctx context.Context, buf []byte, policy readPolicy, timers *readTimers) (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	var n int
+	for n < len(buf) {
+		if policy.minBytes > 0 && n >= policy.minBytes {
+			break
+		}
+		userDeadline, gen := p.userReadDeadline()
+		if expired(userDeadline) {
+			return n, os.ErrDeadlineExceeded
+		}
+		policyDeadline := policy.deadline(timers.start, timers.lastByte)
+		k, err := p.readUntil(ctx, buf[n:], earliest(policyDeadline, userDeadline), gen)
+		n += k
+		if k > 0 {
+			timers.lastByte = time.Now()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			if expired(policyDeadline) {
+				break
+			}
+			// Either the user deadline has expired (checked at the top of the loop)
+			// or it has been changed while waiting.
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+
+	if n == 0 && policy.eofOnTimeout {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Reads available data or waits for it until the deadline.
+// If the deadline has already passed, only the data that is already
+// in the input queue is read.
+//
+// The context and the user deadline are checked after the file deadline is set,
+// so a cancellation or a deadline change that happens later always interrupts the wait
+// (see `interruptOnDone` and `SetReadDeadline`).
+func (p *serialPort) readUntil(ctx context.Context, buf []byte, deadline time.Time, gen uint64) (int, error) {
+	if expired(deadline) {
+		return p.readNow(buf)
+	}
+	if err := p.File.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if _, g := p.userReadDeadline(); g != gen {
+		return 0, os.ErrDeadlineExceeded
+	}
+	n, err := p.File.Read(buf)
+	if err == io.EOF {
+		// With VMIN=1 an empty read means a hangup, which the tty layer
+		// does when the device is gone.
+		err = ErrDeviceRemoved
+	}
+	return n, err
+}
+
+// Reads the data from the input queue without waiting.
+func (p *serialPort) readNow(buf []byte) (int, error) {
+	var n int
+	var readErr error
+	err := p.control(func(fd uintptr) error {
+		n, readErr = unix.Read(int(fd), buf)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if readErr == unix.EAGAIN {
+		return 0, os.ErrDeadlineExceeded
+	}
+	if readErr == nil && n == 0 {
+		return 0, ErrDeviceRemoved // see `readUntil`
+	}
+	if readErr != nil {
+		return 0, os.NewSyscallError("read", readErr)
+	}
+	return n, nil
+}
+
+// Write writes data within the WriteTotal timeout, if any.
+//
+// Like on windows, an expired timeout is not an error:
+// Write returns the number of bytes accepted by the driver.
+func (p *serialPort) Write(buf []byte) (int, error) {
+	n, err := p.write(context.Background(), buf)
+	return n, p.wrapError(err)
+}
+
+func (p *serialPort) write(ctx context.Context, buf []byte) (int, error) {
+	if p == nil || p.File == nil {
+		return 0, ErrInvalidOrNilPort
+	}
+
+	var totalDeadline time.Time
+	if total := p.writeTotal(); total > 0 {
+		totalDeadline = time.Now().Add(total)
+	}
+
+	var n int
+	for {
+		userDeadline, gen := p.userWriteDeadline()
+		if expired(userDeadline) {
+			return n, os.ErrDeadlineExceeded
+		}
+		deadline := earliest(totalDeadline, userDeadline)
+		if err := p.File.SetWriteDeadline(deadline); err != nil {
+			return n, err
+		}
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		chunk := buf[n:]
+		var k int
+		var err error
+		if _, g := p.userWriteDeadline(); g != gen {
+			err = os.ErrDeadlineExceeded
+		} else {
+			k, err = p.writeChunk(ctx, chunk, deadline, gen)
+		}
+		n += k
+		if ctxErr := ctx.Err(); ctxErr != nil && err != nil {
+			return n, ctxErr
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			if expired(totalDeadline) {
+				return n, nil
+			}
+			// See the same place in `read`.
+			continue
+		}
+		if err != nil || n == len(buf) {
+			return n, err
+		}
+	}
+}
+
+// Checks whether a non-zero deadline has passed.
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+// Returns the earliest of two deadlines, where zero time means no deadline.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -19,5 +19,9 @@ func (p *serialPort) SetTimeouts(timeouts Timeouts) error {
 	cto.ReadIntervalTimeout = uint32(timeouts.ReadIntercharacter / time.Millisecond)
 	cto.ReadTotalTimeoutConstant = uint32(timeouts.ReadTotal / time.Millisecond)
 	cto.WriteTotalTimeoutConstant = uint32(timeouts.WriteTotal / time.Millisecond)
-	return setCommTimeouts(p.fd, cto)
+	if err := setCommTimeouts(p.fd, cto); err != nil {
+		return err
+	}
+	p.useTimeouts = true
+	return nil
 }
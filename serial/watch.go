@@ -0,0 +1,136 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// PortEventType tells whether a port has appeared or disappeared.
+type PortEventType int
+
+const (
+	PORT_ADDED PortEventType = iota + 1
+	PORT_REMOVED
+)
+
+func (t PortEventType) String() string {
+	switch t {
+	case PORT_ADDED:
+		return "added"
+	case PORT_REMOVED:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// PortEvent describes a port that has been plugged or unplugged.
+type PortEvent struct {
+	Type PortEventType
+	Port PortInfo  // For PORT_REMOVED it is the last known description of the port.
+	Time time.Time // When the change was noticed.
+}
+
+// Time given to udev to create device nodes and /dev/serial aliases
+// before the ports are listed again after a hot-plug notification.
+const portWatchSettleDelay = time.Millisecond * 200
+
+// Runs a watch in a new goroutine. The step function sends the events of the changes
+// since its previous call, and the wait function blocks until the next change may have happened.
+// The returned channel is closed when ctx is done, when step returns false or when wait fails.
+// The optional release function is called after the channel is closed.
+func runWatch[E any](
+	ctx context.Context,
+	step func(send func(E) bool) bool,
+	wait func(ctx context.Context) error,
+	release func(),
+) <-chan E {
+	events := make(chan E, 16)
+	send := func(e E) bool {
+		select {
+		case events <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	go func() {
+		if release != nil {
+			defer release()
+		}
+		defer close(events)
+		for step(send) && wait(ctx) == nil {
+		}
+	}()
+	return events
+}
+
+// Sends PORT_ADDED events for all listed ports, then an event for each change of the list.
+// The wait function blocks until the list may have changed.
+// The returned channel is closed when ctx is done or when wait fails.
+// The optional release function is called after the channel is closed.
+func watchPorts(
+	ctx context.Context,
+	list func() ([]PortInfo, error),
+	wait func(ctx context.Context) error,
+	release func(),
+) (<-chan PortEvent, error) {
+	ports, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]PortInfo)
+	listed := true // the ports of the first step are listed above
+	step := func(send func(PortEvent) bool) bool {
+		if !listed {
+			if ports, err = list(); err != nil {
+				// Listing may fail while the tree is being changed, so just wait for the next change.
+				return true
+			}
+		}
+		listed = false
+
+		now := time.Now()
+		current := make(map[string]PortInfo, len(ports))
+		for _, port := range ports {
+			current[port.Name] = port
+		}
+		// A port that has been replugged with the same name is reported as removed and added.
+		var removed []string
+		for name, port := range known {
+			if c, ok := current[name]; !ok || !samePort(c, port) {
+				removed = append(removed, name)
+			}
+		}
+		sort.Strings(removed)
+		for _, name := range removed {
+			if !send(PortEvent{Type: PORT_REMOVED, Port: known[name], Time: now}) {
+				return false
+			}
+		}
+		for _, port := range ports {
+			if k, ok := known[port.Name]; !ok || !samePort(k, port) {
+				if !send(PortEvent{Type: PORT_ADDED, Port: port, Time: now}) {
+					return false
+				}
+			}
+		}
+		known = current
+		return true
+	}
+	return runWatch(ctx, step, wait, release), nil
+}
+
+// Tells whether both descriptions belong to the same device.
+// The aliases are not compared, since udev may create them later.
+func samePort(a, b PortInfo) bool {
+	return a.Name == b.Name && a.Driver == b.Driver && a.IsUSB == b.IsUSB &&
+		a.VID == b.VID && a.PID == b.PID && a.SerialNumber == b.SerialNumber
+}
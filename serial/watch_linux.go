@@ -0,0 +1,146 @@
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Watch reports serial ports being plugged and unplugged until ctx is done.
+// The ports present at the call are reported as PORT_ADDED first,
+// so there is no gap between listing the ports and watching them.
+//
+// Changes are noticed with kernel uevents of the tty subsystem (netlink)
+// and with inotify on /dev, whichever is available. After a notification
+// the ports are listed again once `portWatchSettleDelay` has passed.
+func Watch(ctx context.Context) (<-chan PortEvent, error) {
+	return defaultSysfs.watch(ctx)
+}
+
+func (fs sysfs) watch(ctx context.Context) (<-chan PortEvent, error) {
+	n := &portNotifier{signal: make(chan struct{}, 1)}
+
+	uevents, ueventsErr := openUevents()
+	if ueventsErr == nil {
+		n.files = append(n.files, uevents)
+		go n.read(uevents, isTtyUevent)
+	}
+	inotify, inotifyErr := openInotify(fs.dev)
+	if inotifyErr == nil {
+		n.files = append(n.files, inotify)
+		go n.read(inotify, nil)
+	}
+	if len(n.files) == 0 {
+		return nil, errors.Join(ueventsErr, inotifyErr)
+	}
+
+	events, err := watchPorts(ctx, fs.listPorts, n.wait, n.release)
+	if err != nil {
+		n.release()
+		return nil, err
+	}
+	return events, nil
+}
+
+// portNotifier turns netlink and inotify messages into wake ups of watchPorts.
+type portNotifier struct {
+	files  []*os.File
+	signal chan struct{}
+}
+
+// Reads messages until the file is closed.
+// Every message accepted by the optional filter wakes up the watcher.
+func (n *portNotifier) read(f *os.File, filter func(msg []byte) bool) {
+	buf := make([]byte, 8192)
+	for {
+		m, err := f.Read(buf)
+		if err != nil {
+			return
+		}
+		if filter != nil && !filter(buf[:m]) {
+			continue
+		}
+		select {
+		case n.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Waits for a notification and then for the settle delay.
+func (n *portNotifier) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-n.signal:
+	}
+
+	timer := time.NewTimer(portWatchSettleDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-n.signal:
+			// The ports are listed after the delay anyway.
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+func (n *portNotifier) release() {
+	for _, f := range n.files {
+		f.Close()
+	}
+}
+
+// Opens a netlink socket for kernel uevents.
+// The socket is non-blocking, so the returned file is served by the runtime poller.
+func openUevents() (*os.File, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK,
+		unix.SOCK_DGRAM|unix.SOCK_CLOEXEC|unix.SOCK_NONBLOCK, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: 1})
+	if err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+	return os.NewFile(uintptr(fd), "uevent"), nil
+}
+
+// Opens a non-blocking inotify instance watching the creation and removal of files in dir.
+func openInotify(dir string) (*os.File, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	_, err = unix.InotifyAddWatch(fd, dir,
+		unix.IN_CREATE|unix.IN_DELETE|unix.IN_MOVED_FROM|unix.IN_MOVED_TO)
+	if err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+	return os.NewFile(uintptr(fd), "inotify"), nil
+}
+
+// A kernel uevent is "ACTION@DEVPATH" followed by NUL separated KEY=VALUE pairs.
+func isTtyUevent(msg []byte) bool {
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		if bytes.Equal(field, []byte("SUBSYSTEM=tty")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package serial
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Plugs and unplugs a device in a fake tree. The watcher is woken up by inotify on the fake /dev.
+func TestWatchFakeSysfs(t *testing.T) {
+	fs := newFakeSysfs(t)
+	fs.addPort("ttyUSB0", "ftdi_sio", ftdiAttrs("A"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := fs.watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func(expectType PortEventType, expectName string) {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e.Type != expectType || e.Port.Name != filepath.Join(fs.dev, expectName) {
+				t.Errorf("expected %s %s, but got %s %s", expectType, expectName, e.Type, e.Port.Name)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("expected %s %s, but got nothing", expectType, expectName)
+		}
+	}
+
+	next(PORT_ADDED, "ttyUSB0")
+
+	fs.addPort("ttyACM0", "cdc_acm", map[string]string{"idVendor": "2341", "idProduct": "0043"})
+	fs.write(filepath.Join(fs.dev, "ttyACM0"), "")
+	next(PORT_ADDED, "ttyACM0")
+
+	// The fake /dev has no node for ttyUSB0, so another file is removed to wake up the watcher.
+	fs.removePort("ttyUSB0")
+	if err := os.Remove(filepath.Join(fs.dev, "ttyACM0")); err != nil {
+		t.Fatal(err)
+	}
+	next(PORT_REMOVED, "ttyUSB0")
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestIsTtyUevent(t *testing.T) {
+	tty := "add@/devices/pci0000:00/usb1/1-2/1-2:1.0/ttyUSB0/tty/ttyUSB0\x00ACTION=add\x00" +
+		"DEVPATH=/devices/pci0000:00/usb1/1-2/1-2:1.0/ttyUSB0/tty/ttyUSB0\x00SUBSYSTEM=tty\x00DEVNAME=ttyUSB0\x00"
+	usb := "add@/devices/pci0000:00/usb1/1-2\x00ACTION=add\x00SUBSYSTEM=usb\x00DEVTYPE=usb_device\x00"
+	if !isTtyUevent([]byte(tty)) {
+		t.Errorf("expected a tty uevent")
+	}
+	if isTtyUevent([]byte(usb)) {
+		t.Errorf("expected not a tty uevent")
+	}
+}
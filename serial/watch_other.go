@@ -0,0 +1,15 @@
+//go:build !linux
+
+// ------------------------------------------
+// Created by (c) 2024 Serge Reinov.
+// Licensed under the Apache License, Version 2.0.
+// ------------------------------------------
+
+package serial
+
+import "context"
+
+// Watching for hot-plugged ports is not supported on target OS.
+func Watch(_ context.Context) (<-chan PortEvent, error) {
+	return nil, ErrNotImplementedOnOS
+}
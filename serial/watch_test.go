@@ -0,0 +1,97 @@
+package serial
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchPorts(t *testing.T) {
+	usb0 := PortInfo{Name: "/dev/ttyUSB0", Driver: "ftdi_sio", IsUSB: true, VID: 0x0403, PID: 0x6001, SerialNumber: "A"}
+	usb0Aliased := usb0
+	usb0Aliased.ByID = []string{"/dev/serial/by-id/usb-FTDI-A"}
+	usb0Other := usb0
+	usb0Other.SerialNumber = "B"
+	s0 := PortInfo{Name: "/dev/ttyS0", Driver: "serial8250"}
+
+	lists := [][]PortInfo{
+		{s0},              // initial
+		{s0, usb0},        // plugged
+		{s0, usb0Aliased}, // udev has created an alias, not a new port
+		{usb0Aliased},     // ttyS0 is gone
+		{usb0Other},       // replugged with the same name
+		{},                // unplugged
+	}
+	list := func() ([]PortInfo, error) {
+		l := lists[0]
+		lists = lists[1:]
+		return l, nil
+	}
+	wait := func(context.Context) error {
+		if len(lists) == 0 {
+			return errors.New("no more lists")
+		}
+		return nil
+	}
+	released := make(chan struct{})
+
+	events, err := watchPorts(context.Background(), list, wait, func() { close(released) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []PortEvent{
+		{Type: PORT_ADDED, Port: s0},
+		{Type: PORT_ADDED, Port: usb0},
+		{Type: PORT_REMOVED, Port: s0},
+		{Type: PORT_REMOVED, Port: usb0Aliased},
+		{Type: PORT_ADDED, Port: usb0Other},
+		{Type: PORT_REMOVED, Port: usb0Other},
+	}
+	var got []PortEvent
+	for e := range events {
+		if e.Time.IsZero() {
+			t.Errorf("expected event time to be set")
+		}
+		e.Time = time.Time{}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected events %+v, but got %+v", expect, got)
+	}
+	<-released
+}
+
+func TestWatchPortsListError(t *testing.T) {
+	list := func() ([]PortInfo, error) { return nil, errors.New("no sysfs") }
+	wait := func(context.Context) error { return nil }
+	if _, err := watchPorts(context.Background(), list, wait, nil); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestRunWatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := 0
+	step := func(send func(int) bool) bool {
+		steps++
+		if steps == 3 {
+			cancel()
+		}
+		return send(steps)
+	}
+	wait := func(ctx context.Context) error { return ctx.Err() }
+	released := make(chan struct{})
+
+	events := runWatch(ctx, step, wait, func() { close(released) })
+	var got []int
+	for e := range events {
+		got = append(got, e)
+	}
+	<-released
+	if steps != 3 || len(got) > 3 {
+		t.Errorf("expected the watch to stop after 3 steps, but got %d steps and events %v", steps, got)
+	}
+}